@@ -4,10 +4,10 @@ package main
 
 import (
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/commands"
 	"log"
 	"os"
 	"os/exec"
-	"package-manager/internal/app/commands"
 	"path/filepath"
 	"strings"
 )
@@ -18,6 +18,7 @@ func main() {
 
 	if _, ok := os.LookupEnv("LIQUIBASE_HOME"); ok {
 		liquibasehome = os.Getenv("LIQUIBASE_HOME")
+		commands.HomeSource = "LIQUIBASE_HOME"
 	} else {
 		// Find Liquibase Command
 		out, err := exec.Command("which", "liquibase").CombinedOutput()
@@ -40,9 +41,11 @@ func main() {
 			}
 			// Is Symlink
 			liquibasehome, _ = filepath.Split(link)
+			commands.HomeSource = loc + " -> " + link
 		} else {
 			// Not Symlink
 			liquibasehome, _ = filepath.Split(loc)
+			commands.HomeSource = loc
 		}
 	}
 
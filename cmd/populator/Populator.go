@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
 	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/packages"
 )
 
 func checkConfig() {
@@ -20,7 +20,11 @@ func main(){
 	var newPacks = packages.Packages{}
 
 	// read packages from embedded file
-	packs := app.LoadPackages(app.PackagesJSON)
+	packs, err := app.LoadPackages(app.PackagesJSON)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 	for _, p := range packs {
 		m := modules.getByName(p.Name)
 		if m.name != "" {
@@ -32,5 +36,40 @@ func main(){
 	}
 
 	//Write all packages back to manifest.
-	app.WritePackages(newPacks)
+	pwd, err := os.Getwd()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	manifest := pwd + "/internal/app/packages.json"
+	if err = app.WritePackages(manifest, newPacks); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Trust the pinned publisher keys in the next lpm release
+	keys, err := packageKeys(app.PackageKeys, modules)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(pwd+"/internal/app/package-keys.asc", keys, 0664); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Sign manifest for `lpm update` when the signing key is configured
+	if key := os.Getenv("LPM_MANIFEST_KEY"); key != "" {
+		if err = app.SignManifest(manifest, key); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	// A signature of the previous manifest no longer matches
+	fmt.Println("LPM_MANIFEST_KEY is not set, packages.json is not signed.")
+	if err = os.Remove(manifest + app.SignatureSuffix); err != nil && !os.IsNotExist(err) {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
\ No newline at end of file
@@ -1,22 +1,134 @@
 package main
 
 import (
+	"bytes"
 	"encoding/xml"
+	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"github.com/vifraa/gopom"
 	"io"
 	"log"
 	"net/http"
-	"package-manager/internal/app/packages"
 	"strings"
 )
 
+// keyServer lookup of armored OpenPGP public keys by fingerprint
+const keyServer = "https://keyserver.ubuntu.com/pks/lookup?op=get&options=mr&search=0x"
+
 // Artifactory main interface for module artifactory logic
 type Artifactory interface {
 	GetVersions(Module) []*version.Version
 	GetNewVersions(Module, packages.Package) packages.Package
 }
 
+// checksumLengths hex digest length of the checksum files published next to artifacts
+var checksumLengths = map[string]int{"SHA1": 40, "SHA256": 64, "SHA512": 128}
+
+// parseChecksum hex digest of algorithm from checksum file contents, empty when missing or malformed
+func parseChecksum(b []byte, alg string) string {
+	sum := strings.ToLower(strings.TrimSpace(string(b)))
+	n := checksumLengths[alg]
+	if len(sum) < n || strings.Contains(sum, "html") {
+		return ""
+	}
+	return sum[0:n] //Checksum files may append the file name
+}
+
+// addChecksum record digest of algorithm in version checksums, ignoring empty digests
+func addChecksum(ver *packages.Version, alg string, sum string) {
+	if sum == "" {
+		return
+	}
+	if ver.Checksums == nil {
+		ver.Checksums = map[string]string{}
+	}
+	ver.Checksums[alg] = sum
+}
+
+// signatureFingerprint issuer key fingerprint of an armored detached signature, empty when it only names a key id
+func signatureFingerprint(b []byte) string {
+	block, err := armor.Decode(bytes.NewReader(b))
+	if err != nil {
+		return ""
+	}
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return ""
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok || len(sig.IssuerFingerprint) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%X", sig.IssuerFingerprint)
+}
+
+// packageKeys armored public keys pinned in the fingerprints of mods, empty without pins.
+// Keys of current are kept when still pinned, missing ones are fetched from keyServer by their full fingerprint.
+func packageKeys(current []byte, mods Modules) ([]byte, error) {
+	kr, err := packages.ReadKeyring(current)
+	if err != nil {
+		return nil, err
+	}
+	var pinned openpgp.EntityList
+	find := func(es openpgp.EntityList, fp string) *openpgp.Entity {
+		for _, e := range es {
+			if packages.HasFingerprint(e, fp) {
+				return e
+			}
+		}
+		return nil
+	}
+	for _, m := range mods {
+		for _, f := range m.fingerprints {
+			fp := packages.NormalizeFingerprint(f)
+			if find(pinned, fp) != nil {
+				continue
+			}
+			if e := find(kr, fp); e != nil {
+				pinned = append(pinned, e)
+				continue
+			}
+			b, err := utils.HTTPUtil{}.Get(keyServer + fp)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch signing key %s of %s: %w", fp, m.name, err)
+			}
+			es, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(b))
+			if err != nil {
+				return nil, fmt.Errorf("unable to read signing key %s of %s: %w", fp, m.name, err)
+			}
+			e := find(es, fp)
+			if e == nil {
+				return nil, fmt.Errorf("key server returned no key with fingerprint %s for %s", fp, m.name)
+			}
+			pinned = append(pinned, e)
+		}
+	}
+	kr = pinned
+	if len(kr) == 0 {
+		return nil, nil
+	}
+	var out bytes.Buffer
+	w, err := armor.Encode(&out, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range kr {
+		if err = e.Serialize(w); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+	return out.Bytes(), nil
+}
+
 // GetPomFromURL get POM object from remote URL
 func GetPomFromURL(url string) *gopom.Project {
 	resp, err := http.Get(url)
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"github.com/google/go-github/v39/github"
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"golang.org/x/oauth2"
 	"os"
-	"package-manager/internal/app/packages"
-	"package-manager/internal/app/utils"
 	"sort"
 	"strings"
 )
@@ -64,8 +64,21 @@ func (g Github) GetNewVersions(m Module, p packages.Package) packages.Package {
 				ver.Path = a.GetBrowserDownloadURL()
 			}
 			if strings.Contains(a.GetName(), "sha1") {
+				sum, err := utils.HTTPUtil{}.Get(a.GetBrowserDownloadURL())
+				if err != nil || len(sum) < 40 {
+					continue
+				}
 				ver.Algorithm = "SHA1"
-				ver.CheckSum = string(utils.HTTPUtil{}.Get(a.GetBrowserDownloadURL()))[0:40] //Get first 40 character of SHA1 only
+				ver.CheckSum = string(sum)[0:40] //Get first 40 character of SHA1 only
+			}
+			for _, alg := range []string{"SHA256", "SHA512"} {
+				if !strings.Contains(a.GetName(), strings.ToLower(alg)) {
+					continue
+				}
+				sum, err := utils.HTTPUtil{}.Get(a.GetBrowserDownloadURL())
+				if err == nil {
+					addChecksum(&ver, alg, parseChecksum(sum, alg))
+				}
 			}
 		}
 
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/xml"
+	"fmt"
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io"
 	"net/http"
-	"package-manager/internal/app/packages"
-	"package-manager/internal/app/utils"
 	"sort"
 	"strings"
 )
@@ -114,11 +116,31 @@ func (mav Maven) GetNewVersions(m Module, p packages.Package) packages.Package {
 
 		ver.Path = url + filename + ".jar"
 		ver.Algorithm = "SHA1"
-		sha := string(utils.HTTPUtil{}.Get(ver.Path + ".sha1"))
-		if strings.Contains(sha, "html") {
-			ver.CheckSum = ""
-		} else {
-			ver.CheckSum = sha[0:40] //Get first 40 character of SHA1 only
+		b, err := utils.HTTPUtil{}.Read(context.Background(), ver.Path+".sha1")
+		ver.CheckSum = ""
+		if err == nil {
+			ver.CheckSum = parseChecksum(b, "SHA1")
+		}
+		// Stronger checksums are only published for newer releases, SHA1 stays in checksum for older lpm versions
+		for _, alg := range []string{"SHA256", "SHA512"} {
+			b, err = utils.HTTPUtil{}.Read(context.Background(), ver.Path+"."+strings.ToLower(alg))
+			if err == nil {
+				addChecksum(&ver, alg, parseChecksum(b, alg))
+			}
+		}
+		// Maven Central requires a detached signature, it is only recorded when made by a pinned publisher key
+		if len(m.fingerprints) > 0 {
+			b, err = utils.HTTPUtil{}.Read(context.Background(), ver.Path+".asc")
+			if err != nil || strings.Contains(string(b), "html") {
+				fmt.Println("Skipping " + p.Name + " " + tag + ": no signature")
+				continue
+			}
+			fp := m.pinned(signatureFingerprint(b))
+			if fp == "" {
+				fmt.Println("Skipping " + p.Name + " " + tag + ": not signed by a pinned publisher key")
+				continue
+			}
+			ver.Signature, ver.Fingerprint = ver.Path+".asc", fp
 		}
 
 		// Older versions might have bad version patters ending up with a missing sha. Don't add them.
@@ -1,8 +1,8 @@
 package main
 
 import (
-	"testing"
 	"github.com/vifraa/gopom"
+	"testing"
 )
 
 func TestGetCoreVersionFromPom(t *testing.T) {
@@ -2,7 +2,7 @@ package main
 
 import (
 	"github.com/hashicorp/go-version"
-	"package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
 )
 
 //Category module category type
@@ -28,6 +28,7 @@ type Module struct {
 	owner string
 	repo string
 	artifactory Artifactory
+	fingerprints []string // publisher keys expected to sign releases, pinned here so the artifact source can not choose them
 }
 
 //GetVersions for module
@@ -39,3 +40,13 @@ func (m Module) GetVersions() []*version.Version {
 func (m Module) GetNewVersions(p packages.Package) packages.Package {
 	return m.artifactory.GetNewVersions(m, p)
 }
+
+//pinned fingerprint of signing key fp when it is one of the expected publisher keys, empty otherwise
+func (m Module) pinned(fp string) string {
+	for _, e := range m.fingerprints {
+		if packages.NormalizeFingerprint(e) == fp {
+			return fp
+		}
+	}
+	return ""
+}
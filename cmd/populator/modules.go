@@ -18,6 +18,7 @@ end:
 }
 
 func init() {
+    // Signatures of a module are only recorded and its keys only embedded once its publisher fingerprints are pinned
     modules = []Module{
         {
             name:          "liquibase-aws-extension",
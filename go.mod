@@ -1,18 +1,22 @@
-module package-manager
+module github.com/liquibase/liquibase-package-manager
 
 go 1.25.11
 
 require (
+	github.com/ProtonMail/go-crypto v1.3.0
 	github.com/google/go-github/v39 v39.2.0
 	github.com/hashicorp/go-version v1.9.0
 	github.com/spf13/cobra v1.10.2
 	github.com/vifraa/gopom v1.0.0
 	golang.org/x/oauth2 v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/cloudflare/circl v1.6.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 	golang.org/x/crypto v0.45.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
 )
@@ -3,11 +3,12 @@ package app
 import (
 	_ "embed" // Embed Import for Package Files
 	"encoding/json"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io/fs"
 	"os"
-	"package-manager/internal/app/errors"
-	"package-manager/internal/app/packages"
-	"package-manager/internal/app/utils"
+	"path/filepath"
 )
 
 //go:embed "VERSION"
@@ -18,12 +19,20 @@ var version string
 //go:embed "packages.json"
 var PackagesJSON []byte
 
+// PackageKeys OpenPGP public keys of the signers of published packages, refreshed by the populator
+//
+//go:embed "package-keys.asc"
+var PackageKeys []byte
+
 // PackageFile exported for overwrite
 var PackageFile = "packages.json"
 
 // Classpath exported for overwrite
 var Classpath string
 
+// LocalClasspath directory of local installs relative to the working directory, exported for overwrite
+var LocalClasspath = "liquibase_libs"
+
 // ClasspathFiles exported for overwrite
 var ClasspathFiles []fs.FileInfo
 
@@ -33,18 +42,23 @@ func Version() string {
 }
 
 // SetClasspath to switch between global and local modules
-func SetClasspath(global bool, globalpath string, globalpathFiles []fs.FileInfo) {
+func SetClasspath(global bool, globalpath string, globalpathFiles []fs.FileInfo) error {
 	if global {
 		Classpath = globalpath
 		ClasspathFiles = globalpathFiles
-	} else {
-		pwd, err := os.Getwd()
-		if err != nil {
-			errors.Exit(err.Error(), 1)
-		}
-		Classpath = pwd + "/liquibase_libs/"
-		ClasspathFiles, _ = utils.ReadDir(Classpath)
+		return nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
 	}
+	Classpath = LocalClasspath
+	if !filepath.IsAbs(Classpath) {
+		Classpath = filepath.Join(pwd, Classpath)
+	}
+	Classpath += string(os.PathSeparator)
+	ClasspathFiles, _ = utils.ReadDir(Classpath)
+	return nil
 }
 
 // PackagesInClassPath is the packages.json file in global classpath
@@ -54,35 +68,24 @@ func PackagesInClassPath(cp string) bool {
 }
 
 // CopyPackagesToClassPath install packages.json to global classpath
-func CopyPackagesToClassPath(cp string, p []byte) {
-	err := os.WriteFile(cp+PackageFile, p, 0664)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
+func CopyPackagesToClassPath(cp string, p []byte) error {
+	return os.WriteFile(cp+PackageFile, p, 0664)
 }
 
 // LoadPackages get packages from bytes from file
-func LoadPackages(b []byte) packages.Packages {
+func LoadPackages(b []byte) (packages.Packages, error) {
 	var e packages.Packages
-	err := json.Unmarshal(b, &e)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", PackageFile, err)
 	}
-	return e
+	return e, nil
 }
 
-// WritePackages write packages back to file
-func WritePackages(p packages.Packages) {
+// WritePackages write packages to the manifest file at path
+func WritePackages(path string, p packages.Packages) error {
 	b, err := json.MarshalIndent(p, "", "  ")
 	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
-	pwd, err := os.Getwd()
-	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
-	err = os.WriteFile(pwd+"/internal/app/packages.json", b, 0664)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
+		return err
 	}
+	return os.WriteFile(path, b, 0664)
 }
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	_ "embed" // Embed Import for Manifest Keys
+	"encoding/base64"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"os"
+	"strings"
+)
+
+// ManifestKeys public keys trusted to sign packages.json, base64 encoded one per line
+//
+//go:embed "manifest-keys.txt"
+var ManifestKeys string
+
+// SignatureSuffix appended to the manifest location to find its detached signature
+const SignatureSuffix = ".sig"
+
+// ParseManifestKeys base64 encoded ed25519 public keys, one per line or comma separated, ignoring # comments
+func ParseManifestKeys(s string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	sc := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(s, ",", "\n")))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(b) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid manifest key %s, expected a base64 encoded ed25519 public key", line)
+		}
+		keys = append(keys, ed25519.PublicKey(b))
+	}
+	return keys, nil
+}
+
+// VerifyManifest check the detached base64 ed25519 signature of manifest against any trusted key
+func VerifyManifest(manifest []byte, sig []byte, keys []ed25519.PublicKey) error {
+	if len(keys) == 0 {
+		return errors.New(errors.ErrBadSignature, "No trusted manifest keys are configured.")
+	}
+	s, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil || len(s) != ed25519.SignatureSize {
+		return errors.New(errors.ErrBadSignature, "Manifest signature is not a base64 encoded ed25519 signature.")
+	}
+	for _, k := range keys {
+		if ed25519.Verify(k, manifest, s) {
+			return nil
+		}
+	}
+	return errors.New(errors.ErrBadSignature, "Manifest signature does not match any trusted key.")
+}
+
+// SignManifest write the detached signature of packages.json next to it, key is the base64 ed25519 private key or seed
+func SignManifest(path string, key string) error {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return fmt.Errorf("invalid manifest signing key: %w", err)
+	}
+	var pk ed25519.PrivateKey
+	switch len(b) {
+	case ed25519.SeedSize:
+		pk = ed25519.NewKeyFromSeed(b)
+	case ed25519.PrivateKeySize:
+		pk = ed25519.PrivateKey(b)
+	default:
+		return fmt.Errorf("invalid manifest signing key, expected a base64 encoded ed25519 seed or private key")
+	}
+	m, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(pk, m))
+	return os.WriteFile(path+SignatureSuffix, []byte(sig+"\n"), 0664)
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RegistryDir directory in the global lib directory holding the last fetched copy of remote registries
+const RegistryDir = "lpm-registries"
+
+// ReadManifest packages.json contents from an http(s) URL, file:// URL or local path, downloading with h until ctx is done
+func ReadManifest(ctx context.Context, h utils.HTTPUtil, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http") {
+		return os.ReadFile(strings.TrimPrefix(source, "file://"))
+	}
+	return h.Read(ctx, source)
+}
+
+// Registries additional manifests in priority order, each package name is taken from the first registry that has it.
+// Every registry needs a detached signature by one of Keys, like the public manifest, unless it is listed in Insecure.
+// Remote registries are read from the copy stored in Dir and only downloaded when there is none or on Update.
+type Registries struct {
+	Sources  []string            // packages.json URLs or paths
+	Insecure []string            // sources used without checking their signature
+	Keys     []ed25519.PublicKey // keys trusted to sign registries
+	HTTP     utils.HTTPUtil
+	Dir      string // stored copies of remote registries
+}
+
+// Load verified packages of every registry, merged in priority order
+func (r Registries) Load(ctx context.Context) (packages.Packages, error) {
+	var ps packages.Packages
+	for _, s := range r.Sources {
+		b, err := r.read(ctx, s, false)
+		if err != nil {
+			return nil, err
+		}
+		p, err := LoadPackages(b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read registry %s: %w", s, err)
+		}
+		ps = ps.Merge(p.WithRegistry(s))
+	}
+	return ps, nil
+}
+
+// Update download every remote registry again and replace its stored copy once verified
+func (r Registries) Update(ctx context.Context) error {
+	for _, s := range r.Sources {
+		if !strings.HasPrefix(s, "http") {
+			continue
+		}
+		if _, err := r.read(ctx, s, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read verified contents of registry, from the stored copy of remote registries unless refresh
+func (r Registries) read(ctx context.Context, source string, refresh bool) ([]byte, error) {
+	if !strings.HasPrefix(source, "http") {
+		return r.verify(source, source, source+SignatureSuffix)
+	}
+	stored := r.stored(source)
+	if !refresh && exists(stored) {
+		// Stored copies are checked again in case they were changed on disk
+		return r.verify(source, stored, stored+SignatureSuffix)
+	}
+	if r.HTTP.Offline {
+		return nil, errors.Newf(errors.ErrOffline, "Registry %s has not been downloaded yet. Run `lpm update` without --offline first.", source)
+	}
+	b, err := r.HTTP.Read(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read registry %s: %w", source, err)
+	}
+	var sig []byte
+	if !r.insecure(source) {
+		if sig, err = r.HTTP.Read(ctx, source+SignatureSuffix); err != nil {
+			return nil, r.signatureError(source, err)
+		}
+		if err = VerifyManifest(b, sig, r.Keys); err != nil {
+			return nil, r.signatureError(source, err)
+		}
+	}
+	if err = os.MkdirAll(r.Dir, 0775); err != nil {
+		return nil, err
+	}
+	if err = utils.WriteAtomic(stored, bytes.NewReader(b), nil); err != nil {
+		return nil, err
+	}
+	if sig != nil {
+		err = utils.WriteAtomic(stored+SignatureSuffix, bytes.NewReader(sig), nil)
+	}
+	return b, err
+}
+
+// verify contents of registry read from path against the signature at sigPath
+func (r Registries) verify(source string, path string, sigPath string) ([]byte, error) {
+	b, err := os.ReadFile(strings.TrimPrefix(path, "file://"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read registry %s: %w", source, err)
+	}
+	if r.insecure(source) {
+		return b, nil
+	}
+	sig, err := os.ReadFile(strings.TrimPrefix(sigPath, "file://"))
+	if err != nil {
+		return nil, r.signatureError(source, err)
+	}
+	if err = VerifyManifest(b, sig, r.Keys); err != nil {
+		return nil, r.signatureError(source, err)
+	}
+	return b, nil
+}
+
+func (r Registries) signatureError(source string, err error) error {
+	return errors.Newf(errors.ErrBadSignature, "Unable to verify registry %s: %w\nTrust its key with --trusted-key or use --insecure-registry %s to skip the check.", source, err, source)
+}
+
+func (r Registries) insecure(source string) bool {
+	for _, s := range r.Insecure {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// stored location of the last fetched copy of a remote registry
+func (r Registries) stored(source string) string {
+	return filepath.Join(r.Dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(source))))
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
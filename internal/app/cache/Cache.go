@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultDir user level download cache
+var DefaultDir string
+
+func init() {
+	d, err := os.UserCacheDir()
+	if err != nil {
+		d = os.TempDir()
+	}
+	DefaultDir = filepath.Join(d, "lpm")
+}
+
+// Cache download cache in Dir, the zero value uses DefaultDir
+type Cache struct {
+	Dir string
+}
+
+// Entry cached artifact, content addressed by algorithm and checksum
+type Entry struct {
+	Algorithm string
+	CheckSum  string
+	Filename  string
+	Size      int64
+	cache     Cache
+}
+
+// Path location of cached artifact on disk
+func (e Entry) Path() string {
+	return filepath.Join(e.cache.entryDir(e.Algorithm, e.CheckSum), e.Filename)
+}
+
+// Verify re-hash cached artifact and compare with its checksum
+func (e Entry) Verify() error {
+	sum, err := utils.FileChecksum(e.Algorithm, e.Path())
+	if err != nil {
+		return err
+	}
+	if sum != e.CheckSum {
+		return errors.Newf(errors.ErrChecksumMismatch, "checksum mismatch for %s: got %s", e.Filename, sum)
+	}
+	return nil
+}
+
+// signatureFile detached signature stored next to a cached artifact, hidden from Lookup
+const signatureFile = ".signature"
+
+// Signature detached signature stored with cached artifact by PutSignature
+func (e Entry) Signature() ([]byte, error) {
+	return os.ReadFile(filepath.Join(e.cache.entryDir(e.Algorithm, e.CheckSum), signatureFile))
+}
+
+// PutSignature store verified detached signature with cached artifact for offline installs
+func (e Entry) PutSignature(sig []byte) error {
+	return utils.WriteAtomic(filepath.Join(e.cache.entryDir(e.Algorithm, e.CheckSum), signatureFile), bytes.NewReader(sig), nil)
+}
+
+// CopyTo copy cached artifact to destination, the copy does not share storage with the cache
+func (e Entry) CopyTo(dst string) error {
+	source, err := os.Open(e.Path())
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	return utils.WriteAtomic(dst, source, nil)
+}
+
+// Remove delete cached artifact, refusing entries that do not resolve inside the cache directory
+func (e Entry) Remove() error {
+	d := e.cache.entryDir(e.Algorithm, e.CheckSum)
+	if rel, err := filepath.Rel(e.cache.dir(), d); !utils.ValidChecksum(e.Algorithm, e.CheckSum) || err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("refusing to remove %s outside of cache %s", d, e.cache.dir())
+	}
+	return os.RemoveAll(d)
+}
+
+// Lookup cached artifact by checksum, invalid checksums are never found
+func (c Cache) Lookup(alg string, sum string) (Entry, bool) {
+	if !utils.ValidChecksum(alg, sum) {
+		return Entry{}, false
+	}
+	files, err := utils.ReadDir(c.entryDir(alg, sum))
+	if err != nil {
+		return Entry{}, false
+	}
+	for _, f := range files {
+		if f.Mode().IsRegular() && !strings.HasPrefix(f.Name(), ".") {
+			return Entry{Algorithm: strings.ToUpper(alg), CheckSum: sum, Filename: f.Name(), Size: f.Size(), cache: c}, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Put store verified artifact contents in cache
+func (c Cache) Put(alg string, sum string, filename string, r io.Reader) (Entry, error) {
+	e := Entry{Algorithm: strings.ToUpper(alg), CheckSum: sum, Filename: filename, cache: c}
+	if !utils.ValidChecksum(alg, sum) {
+		return e, fmt.Errorf("invalid %s checksum %q", alg, sum)
+	}
+	if filepath.Base(filename) != filename || strings.HasPrefix(filename, ".") {
+		return e, fmt.Errorf("invalid cache filename %q", filename)
+	}
+	if err := os.MkdirAll(c.entryDir(alg, sum), 0775); err != nil {
+		return e, err
+	}
+	if err := utils.WriteAtomic(e.Path(), r, nil); err != nil {
+		return e, err
+	}
+	if fi, err := os.Stat(e.Path()); err == nil {
+		e.Size = fi.Size()
+	}
+	return e, nil
+}
+
+// List all cached artifacts
+func (c Cache) List() ([]Entry, error) {
+	var r []Entry
+	algs, err := utils.ReadDir(c.dir())
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range algs {
+		if !a.IsDir() {
+			continue
+		}
+		sums, err := utils.ReadDir(filepath.Join(c.dir(), a.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sums {
+			if e, ok := c.Lookup(a.Name(), s.Name()); ok {
+				r = append(r, e)
+			}
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Filename < r[j].Filename })
+	return r, nil
+}
+
+// Clean remove every cached artifact
+func (c Cache) Clean() error {
+	return os.RemoveAll(c.dir())
+}
+
+// Location directory of the cache
+func (c Cache) Location() string {
+	return c.dir()
+}
+
+func (c Cache) dir() string {
+	if c.Dir == "" {
+		return DefaultDir
+	}
+	return c.Dir
+}
+
+func (c Cache) entryDir(alg string, sum string) string {
+	return filepath.Join(c.dir(), strings.ToLower(alg), sum)
+}
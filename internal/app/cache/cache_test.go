@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// SHA1 of "DriverSHA1"
+const driverSum = "70daefe06dd19c073920273e02cfc712951795ea"
+
+func TestPutLookup(t *testing.T) {
+	c := Cache{Dir: t.TempDir()}
+	if _, ok := c.Lookup("SHA1", driverSum); ok {
+		t.Fatalf("Expected empty cache")
+	}
+	if _, err := c.Put("SHA1", driverSum, "driver-0.2.0.jar", strings.NewReader("DriverSHA1")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	e, ok := c.Lookup("SHA1", driverSum)
+	if !ok {
+		t.Fatalf("Unable to find cached package")
+	}
+	if e.Filename != "driver-0.2.0.jar" || e.Size != 10 {
+		t.Errorf("Lookup() = %v", e)
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestEntry_Verify(t *testing.T) {
+	c := Cache{Dir: t.TempDir()}
+	e, _ := c.Put("SHA1", driverSum, "driver-0.2.0.jar", strings.NewReader("Tampered"))
+	if err := e.Verify(); err == nil {
+		t.Fatalf("Expected checksum mismatch for tampered entry")
+	}
+}
+
+func TestEntry_CopyTo(t *testing.T) {
+	c := Cache{Dir: t.TempDir()}
+	e, _ := c.Put("SHA1", driverSum, "driver-0.2.0.jar", strings.NewReader("DriverSHA1"))
+	dst := filepath.Join(t.TempDir(), "driver-0.2.0.jar")
+	if err := e.CopyTo(dst); err != nil {
+		t.Fatalf("CopyTo() error = %v", err)
+	}
+	b, _ := os.ReadFile(dst)
+	if string(b) != "DriverSHA1" {
+		t.Errorf("Expected copied contents DriverSHA1 but got %s", b)
+	}
+	// Damage to the installed copy must not reach the cache
+	os.WriteFile(dst, []byte("Tampered"), 0664)
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify() error = %v after changing the copy", err)
+	}
+}
+
+func TestInvalidChecksum(t *testing.T) {
+	c := Cache{Dir: filepath.Join(t.TempDir(), "lpm")}
+	victim := filepath.Dir(c.Dir)
+	os.WriteFile(filepath.Join(victim, "keep.txt"), []byte("keep"), 0664)
+	for _, sum := range []string{"../..", "..", "", strings.ToUpper(driverSum), driverSum[:39]} {
+		if _, ok := c.Lookup("SHA1", sum); ok {
+			t.Errorf("Lookup(%q) found an entry", sum)
+		}
+		if _, err := c.Put("SHA1", sum, "driver-0.2.0.jar", strings.NewReader("DriverSHA1")); err == nil {
+			t.Errorf("Put(%q) expected error", sum)
+		}
+		if err := (Entry{Algorithm: "SHA1", CheckSum: sum, cache: c}).Remove(); err == nil {
+			t.Errorf("Remove(%q) expected error", sum)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(victim, "keep.txt")); err != nil {
+		t.Errorf("file outside the cache was removed: %v", err)
+	}
+}
+
+func TestListClean(t *testing.T) {
+	c := Cache{Dir: t.TempDir()}
+	c.Put("SHA1", driverSum, "driver-0.2.0.jar", strings.NewReader("DriverSHA1"))
+	c.Put("SHA256", "94c74ea180983e2ec16451fed233c9f6d3d47572133cae84a0adc7c9fd7e1dd4", "driver-0.0.1.jar", strings.NewReader("DriverSHA256"))
+	entries, err := c.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Filename != "driver-0.0.1.jar" {
+		t.Errorf("List() = %v", entries)
+	}
+	if err = c.Clean(); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if entries, _ = c.List(); len(entries) != 0 {
+		t.Errorf("Expected empty cache after c.Clean() but got %v", entries)
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+var cacheDryRun bool
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the shared download cache",
+}
+
+// cacheListCmd represents the cache list command
+var cacheListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List Cached Packages",
+	Aliases: []string{"ls"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := downloadCache()
+		entries, err := c.List()
+		if err != nil {
+			return err
+		}
+		fmt.Println(c.Location())
+		if len(entries) == 0 {
+			return nil
+		}
+		var prefix string
+		fmt.Printf("%-4s %-48s %-8s %-12s %s\n", "   ", "File", "Algo", "Size", "Checksum")
+		for i, e := range entries {
+			if (i + 1) == len(entries) {
+				prefix = "└──"
+			} else {
+				prefix = "├──"
+			}
+			fmt.Printf("%-4s %-48s %-8s %-12s %s\n", prefix, e.Filename, e.Algorithm, strconv.FormatInt(e.Size, 10), e.CheckSum)
+		}
+		return nil
+	},
+}
+
+// cacheVerifyCmd represents the cache verify command
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify Checksums of Cached Packages",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := downloadCache().List()
+		if err != nil {
+			return err
+		}
+		var failed int
+		for _, e := range entries {
+			if err := e.Verify(); err != nil {
+				failed++
+				fmt.Println(e.Filename + " is corrupt: " + err.Error())
+				if !cacheDryRun {
+					e.Remove()
+					fmt.Println(e.Filename + " removed from cache.")
+				}
+				continue
+			}
+			fmt.Println(e.Filename + " verified.")
+		}
+		if failed > 0 {
+			return errors.New(errors.ErrChecksumMismatch, strconv.Itoa(failed)+" of "+strconv.Itoa(len(entries))+" cached package(s) failed verification.")
+		}
+		fmt.Println(strconv.Itoa(len(entries)) + " cached package(s) verified.")
+		return nil
+	},
+}
+
+// cacheCleanCmd represents the cache clean command
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove All Cached Packages",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := downloadCache()
+		if err := c.Clean(); err != nil {
+			return fmt.Errorf("Unable to clean cache located at %s: %w", c.Location(), err)
+		}
+		fmt.Println("Cache cleaned at " + c.Location())
+		return nil
+	},
+}
+
+// downloadCache cache in the configured cache-dir, the user cache directory by default
+func downloadCache() cache.Cache {
+	return cache.Cache{Dir: conf.String("cache-dir", "")}
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheVerifyCmd.Flags().BoolVar(&cacheDryRun, "dry-run", false, "report corrupt entries without removing them")
+}
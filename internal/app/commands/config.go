@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/config"
+	"github.com/spf13/cobra"
+)
+
+var project bool
+
+// configRecord structured output for a single setting
+type configRecord struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage lpm Settings",
+	Long: `Manage lpm settings in the user config file (` + config.UserFile + `)
+or the project config file (` + config.ProjectFile + `). Environment variables override
+config files and flags override both.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Settings do not depend on the Liquibase installation, and unknown or invalid settings can be repaired with set
+		if err := preRun(cmd); err != nil && err != confErr {
+			return err
+		}
+		return nil
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List Configured Settings",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if confErr != nil {
+			return confErr
+		}
+		var rs []configRecord
+		for _, v := range conf.Values() {
+			rs = append(rs, configRecord{Key: v.Key, Value: v.Value, Source: v.Source})
+		}
+		if structured() {
+			if rs == nil {
+				rs = []configRecord{}
+			}
+			return printStructured(rs)
+		}
+		var prefix string
+		fmt.Printf("%-4s %-16s %-8s %s\n", "   ", "Key", "Source", "Value")
+		for i, r := range rs {
+			if (i + 1) == len(rs) {
+				prefix = "└──"
+			} else {
+				prefix = "├──"
+			}
+			fmt.Printf("%-4s %-16s %-8s %s\n", prefix, r.Key, r.Source, r.Value)
+		}
+		return nil
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:       "get [KEY]",
+	Short:     "Print a Setting",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: configKeys(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.Lookup(args[0]); err != nil {
+			return err
+		}
+		if confErr != nil {
+			return confErr
+		}
+		v := conf[args[0]]
+		if structured() {
+			return printStructured(configRecord{Key: args[0], Value: v.Value, Source: v.Source})
+		}
+		fmt.Println(v.Value)
+		return nil
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set [KEY] [VALUE]",
+	Short: "Change a Setting",
+	Long: `Change a setting in the user config file, or the project config file with --project.
+List values are comma separated. An empty value removes the setting, also an unknown one.`,
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: configKeys(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[1] != "" {
+			s, err := config.Lookup(args[0])
+			if err != nil {
+				return err
+			}
+			if err = s.Validate(args[1]); err != nil {
+				return err
+			}
+		}
+		path := config.UserFile
+		if project {
+			path = config.ProjectFile
+		}
+		// Other settings are kept as they are, so a file with unknown or invalid settings can be repaired
+		f, err := config.ReadUnchecked(path)
+		if err != nil {
+			return err
+		}
+		if args[1] == "" {
+			delete(f, args[0])
+		} else {
+			f[args[0]] = args[1]
+		}
+		if err = f.Write(path); err != nil {
+			return fmt.Errorf("unable to write config %s: %w", path, err)
+		}
+		fmt.Println(args[0] + " updated in " + path)
+		return nil
+	},
+}
+
+// configKeys settings keys for completion
+func configKeys() []string {
+	var r []string
+	for _, s := range config.Settings {
+		r = append(r, s.Key+"\t"+s.Usage)
+	}
+	return r
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configSetCmd.Flags().BoolVar(&project, "project", false, "write the project config file instead of the user config file")
+}
@@ -2,11 +2,10 @@ package commands
 
 import (
 	"fmt"
-    "sort"
     "github.com/hashicorp/go-version"
-    "package-manager/internal/app"
-    "package-manager/internal/app/errors"
+    "github.com/liquibase/liquibase-package-manager/internal/app"
     "github.com/spf13/cobra"
+    "sort"
 )
 
 // dedupeCmd represents the dedupe command
@@ -14,8 +13,9 @@ var dedupeCmd = &cobra.Command{
 	Use:   "dedupe",
     Short: "Deduplicate Packages",
     Args:  cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
+        var rs []record
         for _, p := range packs.GetInstalled(app.ClasspathFiles) {
             var installed []*version.Version
             for _, v := range p.Versions {
@@ -30,6 +30,27 @@ var dedupeCmd = &cobra.Command{
             }
 
             sort.Sort(sort.Reverse(version.Collection(installed)))
+            latest := p.GetLatestVersion(liquibase.Version).Tag
+            for i, v := range installed {
+                rec := record{Package: p.Name, Category: p.Category, Installed: p.GetVersion(v.Original()).Tag, Latest: latest, Classpath: app.Classpath, Action: actionKept}
+                if i > 0 && dryRun {
+                    rec.Action = actionWouldRemove
+                } else if i > 0 {
+                    rec.Action = actionRemoved
+                }
+                rs = append(rs, rec)
+            }
+            if structured() {
+                if !dryRun {
+                    for _, v := range installed[1:] {
+                        ver := p.GetVersion(v.Original())
+                        if err := p.Remove(app.Classpath, ver); err != nil {
+                            return fmt.Errorf("Unable to remove %s from classpath.", ver.GetFilename())
+                        }
+                    }
+                }
+                continue
+            }
             fmt.Println(app.Classpath)
             var r []string
 
@@ -55,15 +76,18 @@ var dedupeCmd = &cobra.Command{
                     fmt.Println()
                     ver := p.GetVersion(v.Original())
                     fmt.Println("removing " + p.Name + "@" + ver.Tag + " from classpath")
-                    err := p.Remove(app.Classpath, ver)
-                    if err != nil {
-                        errors.Exit("Unable to remove "+ver.GetFilename()+" from classpath.", 1)
+                    if err := p.Remove(app.Classpath, ver); err != nil {
+                        return fmt.Errorf("Unable to remove %s from classpath.", ver.GetFilename())
                     }
                     fmt.Println(ver.GetFilename() + " successfully uninstalled from classpath.")
                 }
             }
             fmt.Println()
         }
+        if structured() {
+            return printRecords(rs)
+        }
+        return nil
 	},
 }
 
@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/config"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severities of doctor findings
+const (
+	severityOK    = "ok"
+	severityInfo  = "info"
+	severityWarn  = "warn"
+	severityError = "error"
+)
+
+// staleAfter age after which the installed package manifest is reported as stale
+const staleAfter = 90 * 24 * time.Hour
+
+// finding result of a single doctor check
+type finding struct {
+	Check    string `json:"check" yaml:"check"`
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+	Hint     string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+// configErr failure loading the package manifest, reported by doctor instead of aborting
+var configErr error
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the Liquibase and lpm Environment",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := preRun(cmd); err != nil && err != confErr {
+			return err
+		}
+		// Run every check even when the environment is broken, initConfig failures are reported as findings
+		if libErr == nil {
+			configErr = initConfig(cmd.Context())
+		}
+		if app.Classpath == "" {
+			return app.SetClasspath(false, globalpath, globalpathFiles)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var fs []finding
+		fs = append(fs, checkHome()...)
+		fs = append(fs, checkConfig())
+		fs = append(fs, checkLiquibaseVersion())
+		fs = append(fs, checkManifest()...)
+		fs = append(fs, checkSignatures())
+		fs = append(fs, checkClasspath("local", app.Classpath)...)
+		fs = append(fs, checkClasspath("global", globalpath)...)
+		fs = append(fs, checkWritable("global", globalpath), checkWritable("local", filepath.Dir(filepath.Clean(app.Classpath))))
+		fs = append(fs, checkJava(cmd.Context()))
+
+		var errs int
+		for _, f := range fs {
+			if f.Severity == severityError {
+				errs++
+			}
+		}
+		if structured() {
+			if err := printStructured(fs); err != nil {
+				return err
+			}
+		} else {
+			for _, f := range fs {
+				fmt.Printf("[%-5s] %-10s %s\n", strings.ToUpper(f.Severity), f.Check, f.Message)
+				if f.Hint != "" {
+					fmt.Printf("%-19s %s\n", "", f.Hint)
+				}
+			}
+		}
+		if errs > 0 {
+			return fmt.Errorf("%d problem(s) found.", errs)
+		}
+		return nil
+	},
+}
+
+// checkHome how the Liquibase home was located and whether it contains Liquibase
+func checkHome() []finding {
+	home := liquibase.Homepath
+	r := []finding{{Check: "home", Severity: severityOK, Message: home + " (from " + HomeSource + ")"}}
+	if fi, err := os.Stat(home); err != nil || !fi.IsDir() {
+		r[0].Severity = severityError
+		r[0].Hint = "Set LIQUIBASE_HOME to the directory containing the liquibase launcher."
+		return r
+	}
+	if !filepath.IsAbs(home) {
+		r = append(r, finding{Check: "home", Severity: severityWarn,
+			Message: "Liquibase home is relative to the working directory, the liquibase symlink likely has a relative target.",
+			Hint:    "Set LIQUIBASE_HOME to an absolute path."})
+	}
+	if libErr != nil {
+		r = append(r, finding{Check: "home", Severity: severityError, Message: libErr.Error()})
+	}
+	jars := []string{"liquibase.jar", "internal/lib/liquibase-core.jar", "internal/lib/liquibase-commercial.jar"}
+	for _, j := range jars {
+		if _, err := os.Stat(home + j); err == nil {
+			return r
+		}
+	}
+	r = append(r, finding{Check: "home", Severity: severityError,
+		Message: "No liquibase.jar or internal/lib/liquibase-core.jar found in " + home,
+		Hint:    "Set LIQUIBASE_HOME to the Liquibase installation directory."})
+	return r
+}
+
+// checkConfig whether the config files load
+func checkConfig() finding {
+	if confErr != nil {
+		return finding{Check: "config", Severity: severityError, Message: confErr.Error(),
+			Hint: "Fix the setting with `lpm config set KEY VALUE` (add --project for " + config.ProjectFile + "), an empty value removes it, or correct the LPM_ environment variable."}
+	}
+	return finding{Check: "config", Severity: severityOK, Message: strconv.Itoa(len(conf)) + " setting(s) configured"}
+}
+
+// checkSignatures whether any key is trusted to check package signatures
+func checkSignatures() finding {
+	if n := len(trustedPackageKeys()); n > 0 {
+		return finding{Check: "signature", Severity: severityOK, Message: strconv.Itoa(n) + " key(s) trusted for package signatures"}
+	}
+	return finding{Check: "signature", Severity: severityWarn, Message: "No keys are trusted for package signatures, signatures are not checked.",
+		Hint: "Pass --keyring with the OpenPGP public keys of package publishers or add them to " + globalpath + KeyringFile + "."}
+}
+
+// checkLiquibaseVersion whether the Liquibase version was read or fell back to 0.0.0
+func checkLiquibaseVersion() finding {
+	if liquibase.Version == nil || liquibase.Version.String() == "0.0.0" {
+		return finding{Check: "version", Severity: severityWarn,
+			Message: "Unable to detect the Liquibase version, falling back to 0.0.0. Only drivers are considered compatible.",
+			Hint:    "Check that LIQUIBASE_HOME points at a complete Liquibase installation."}
+	}
+	return finding{Check: "version", Severity: severityOK, Message: "liquibase v" + liquibase.Version.String()}
+}
+
+// checkManifest whether packages.json in lib/ loads and is current
+func checkManifest() []finding {
+	path := globalpath + app.PackageFile
+	if configErr != nil {
+		return []finding{{Check: "manifest", Severity: severityError, Message: configErr.Error(),
+			Hint: "Run `lpm update` to replace " + path + "."}}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return []finding{{Check: "manifest", Severity: severityError, Message: err.Error()}}
+	}
+	r := []finding{{Check: "manifest", Severity: severityOK, Message: path + " (" + strconv.Itoa(len(packs)) + " packages)"}}
+
+	// A manifest missing versions bundled with this lpm release has not been updated since an older release
+	embedded, err := app.LoadPackages(app.PackagesJSON)
+	if err == nil {
+		installed, _ := loadInstalledManifest(path)
+		var missing int
+		for _, p := range embedded {
+			ip := installed.GetByName(p.Name)
+			for _, v := range p.Versions {
+				if ip.GetVersion(v.Tag).Tag == "" {
+					missing++
+				}
+			}
+		}
+		if missing > 0 {
+			r = append(r, finding{Check: "manifest", Severity: severityWarn,
+				Message: strconv.Itoa(missing) + " version(s) bundled with lpm " + strings.TrimSpace(app.Version()) + " are missing from " + path,
+				Hint:    "Run `lpm update`."})
+		}
+	}
+	if age := time.Since(fi.ModTime()); age > staleAfter {
+		r = append(r, finding{Check: "manifest", Severity: severityInfo,
+			Message: "Package manifest last updated " + strconv.Itoa(int(age.Hours()/24)) + " days ago.",
+			Hint:    "Run `lpm update`."})
+	}
+	return r
+}
+
+func loadInstalledManifest(path string) (packages.Packages, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return app.LoadPackages(b)
+}
+
+// checkClasspath duplicate versions and jars unknown to the manifest
+func checkClasspath(name string, cp string) []finding {
+	files, err := os.ReadDir(cp)
+	if os.IsNotExist(err) {
+		return []finding{{Check: name, Severity: severityOK, Message: cp + " does not exist yet"}}
+	}
+	if err != nil {
+		return []finding{{Check: name, Severity: severityError, Message: err.Error()}}
+	}
+	managed := map[string]bool{}
+	var r []finding
+	for _, p := range packs {
+		var installed []string
+		for _, v := range p.Versions {
+			for _, f := range files {
+				if f.Name() == v.GetFilename() {
+					managed[f.Name()] = true
+					installed = append(installed, v.Tag)
+				}
+			}
+		}
+		if len(installed) > 1 {
+			r = append(r, finding{Check: name, Severity: severityWarn,
+				Message: p.Name + " is installed more than once: " + strings.Join(installed, ", "),
+				Hint:    dedupeHint(name, cp)})
+		}
+	}
+	var unmanaged []string
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".jar") && !managed[f.Name()] {
+			unmanaged = append(unmanaged, f.Name())
+		}
+	}
+	if len(unmanaged) > 0 {
+		// The global lib directory ships with jars bundled by Liquibase itself
+		f := finding{Check: name, Severity: severityInfo, Message: strconv.Itoa(len(unmanaged)) + " jar(s) in " + cp + " are not managed by lpm"}
+		if name == "local" {
+			f.Severity = severityWarn
+			f.Message += ": " + strings.Join(unmanaged, ", ")
+			f.Hint = "Remove them or add the matching packages with `lpm add`."
+		}
+		r = append(r, f)
+	}
+	if len(r) == 0 {
+		r = append(r, finding{Check: name, Severity: severityOK, Message: cp})
+	}
+	return r
+}
+
+func dedupeHint(name string, cp string) string {
+	if name == "global" {
+		return "Remove the older jars from " + cp + "."
+	}
+	return "Run `lpm dedupe`."
+}
+
+// checkWritable whether packages can be installed in dir
+func checkWritable(name string, dir string) finding {
+	f, err := os.CreateTemp(dir, ".lpm-doctor-*")
+	if err != nil {
+		return finding{Check: "write", Severity: severityError, Message: "Unable to write to " + name + " directory " + dir,
+			Hint: "Check the directory permissions or run lpm as a user that owns it."}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return finding{Check: "write", Severity: severityOK, Message: dir + " is writable"}
+}
+
+// checkJava Java runtime Liquibase will use
+func checkJava(ctx context.Context) finding {
+	var candidates []string
+	if jh := os.Getenv("JAVA_HOME"); jh != "" {
+		candidates = append(candidates, filepath.Join(jh, "bin", "java"))
+	}
+	candidates = append(candidates, filepath.Join(liquibase.Homepath, "jre", "bin", "java"))
+	if p, err := exec.LookPath("java"); err == nil {
+		candidates = append(candidates, p)
+	}
+	for _, java := range candidates {
+		if _, err := exec.LookPath(java); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		out, err := exec.CommandContext(ctx, java, "-version").CombinedOutput()
+		cancel()
+		if err != nil {
+			return finding{Check: "java", Severity: severityError, Message: java + " -version failed: " + err.Error()}
+		}
+		first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		return finding{Check: "java", Severity: severityOK, Message: java + ": " + strings.TrimSpace(first)}
+	}
+	return finding{Check: "java", Severity: severityWarn, Message: "No Java runtime found in JAVA_HOME, the Liquibase home or PATH.",
+		Hint: "Install Java or set JAVA_HOME, Liquibase needs it to run."}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
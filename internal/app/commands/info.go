@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+// versionRecord structured output for a single package version
+type versionRecord struct {
+	Package         string `json:"package" yaml:"package"`
+	Category        string `json:"category" yaml:"category"`
+	Version         string `json:"version" yaml:"version"`
+	LiquibaseCore   string `json:"liquibaseCore" yaml:"liquibaseCore"`
+	Algorithm       string `json:"algorithm" yaml:"algorithm"`
+	Path            string `json:"path" yaml:"path"`
+	Registry        string `json:"registry" yaml:"registry"`
+	Compatible      bool   `json:"compatible" yaml:"compatible"`
+	InstalledGlobal bool   `json:"installedGlobal" yaml:"installedGlobal"`
+	InstalledLocal  bool   `json:"installedLocal" yaml:"installedLocal"`
+}
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info [PACKAGE]",
+	Short: "Show Package Versions and Compatibility",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := packs.GetByName(args[0])
+		if p.Name == "" {
+			return errors.New(errors.ErrPackageNotFound, "Package '"+args[0]+"' not found.")
+		}
+
+		var rs []versionRecord
+		for _, v := range p.Versions {
+			alg, _ := v.Digest()
+			rs = append(rs, versionRecord{
+				Package:         p.Name,
+				Category:        p.Category,
+				Version:         v.Tag,
+				LiquibaseCore:   v.LiquibaseCore,
+				Algorithm:       alg,
+				Path:            v.Path,
+				Registry:        p.Registry,
+				Compatible:      p.IsCompatible(v, liquibase.Version),
+				InstalledGlobal: v.InClassPath(globalpathFiles),
+				InstalledLocal:  v.InClassPath(app.ClasspathFiles),
+			})
+		}
+		if structured() {
+			if rs == nil {
+				rs = []versionRecord{}
+			}
+			return printStructured(rs)
+		}
+
+		fmt.Println(p.Name + " (" + p.Category + ")")
+		fmt.Println("registry " + p.Registry)
+		if liquibase.Version != nil {
+			fmt.Println("liquibase v" + liquibase.Version.String())
+		}
+		var prefix string
+		fmt.Printf("%-4s %-20s %-12s %-10s %-11s %-16s %s\n", "   ", "Version", "Liquibase", "Algorithm", "Compatible", "Installed", "Path")
+		for i, r := range rs {
+			if (i + 1) == len(rs) {
+				prefix = "└──"
+			} else {
+				prefix = "├──"
+			}
+			fmt.Printf("%-4s %-20s %-12s %-10s %-11s %-16s %s\n", prefix, r.Version, r.LiquibaseCore, r.Algorithm, strconv.FormatBool(r.Compatible), installedWhere(r), r.Path)
+		}
+		return nil
+	},
+}
+
+// installedWhere classpaths a version is installed in
+func installedWhere(r versionRecord) string {
+	switch {
+	case r.InstalledGlobal && r.InstalledLocal:
+		return "global,local"
+	case r.InstalledGlobal:
+		return "global"
+	case r.InstalledLocal:
+		return "local"
+	}
+	return "-"
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
@@ -3,58 +3,65 @@ package commands
 import (
 	"fmt"
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/pkg/lpm"
 	"github.com/spf13/cobra"
-	"package-manager/internal/app"
-	"package-manager/internal/app/dependencies"
-	"package-manager/internal/app/errors"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install packages listed in liquibase.json file",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		if global {
-			errors.Exit("Can not install packages from liquibase.json globally", 1)
+		res, err := client.Install(cmd.Context())
+		if err != nil {
+			return err
 		}
-
-		d := dependencies.Dependencies{}
-		d.Read()
-
-		for _, dep := range d.Dependencies {
-			p := packs.GetByName(dep.GetName())
-			v := p.GetVersion(dep.GetVersion())
-			if p.Category != "driver" {
-				if liquibase.Version != nil {
-					core, _ := version.NewVersion(v.LiquibaseCore)
-					if liquibase.Version.LessThan(core) {
-						errors.Exit(p.Name+"@"+v.Tag+" is not compatible with liquibase v"+liquibase.Version.String()+". Please consider updating liquibase.", 1)
-					}
-				}
-			}
-			if v.InClassPath(app.ClasspathFiles) {
-				errors.Exit(p.Name+" is already installed.", 1)
-			}
-			if !v.PathIsHTTP() {
-				v.CopyToClassPath(app.Classpath)
-			} else {
-				v.DownloadToClassPath(app.Classpath)
-			}
-			fmt.Println(v.GetFilename() + " successfully installed in classpath.")
+		for _, r := range res.Installed {
+			fmt.Println(r.Filename + " successfully installed in classpath.")
 		}
+		printUnverified(res.Unverified)
 
-		minVer, _ := version.NewVersion("4.6.2")
-		if liquibase.Version != nil && !liquibase.Version.GreaterThanOrEqual(minVer) {
-			p := "-cp liquibase_libs/*:" + globalpath + "*:" + liquibase.Homepath + "liquibase.jar"
-			fmt.Println()
-			fmt.Println("---------- IMPORTANT ----------")
-			fmt.Println("Add the following JAVA_OPTS to your CLI:")
-			fmt.Println("export JAVA_OPTS=\"" + p + "\"")
-		}
+		printJavaOpts()
+		return nil
 	},
 }
 
+// printUnverified warn about packages installed without a signature check
+func printUnverified(rs []lpm.Resolution) {
+	// Without any trusted key nothing is checked, doctor reports that once instead of every install
+	if len(rs) == 0 || len(trustedPackageKeys()) == 0 {
+		return
+	}
+	var names []string
+	for _, r := range rs {
+		names = append(names, r.Name+"@"+r.Version)
+	}
+	fmt.Fprintln(os.Stderr, "WARNING: signatures were not verified for "+strings.Join(names, ", ")+".")
+	fmt.Fprintln(os.Stderr, "Use --keyring with the keys of their publishers to reject unsigned packages.")
+}
+
+// printJavaOpts hint the classpath when Liquibase does not load the local classpath itself,
+// before 4.6.2 or when it is not liquibase_libs
+func printJavaOpts() {
+	minVer, _ := version.NewVersion("4.6.2")
+	custom := filepath.Clean(app.LocalClasspath) != "liquibase_libs"
+	if !custom && (liquibase.Version == nil || liquibase.Version.GreaterThanOrEqual(minVer)) {
+		return
+	}
+	p := "-cp " + filepath.ToSlash(filepath.Clean(app.LocalClasspath)) + "/*:" + globalpath + "*:" + liquibase.Homepath + "liquibase.jar"
+	fmt.Println()
+	fmt.Println("---------- IMPORTANT ----------")
+	fmt.Println("Add the following JAVA_OPTS to your CLI:")
+	fmt.Println("export JAVA_OPTS=\"" + p + "\"")
+}
+
 func init() {
 	rootCmd.AddCommand(installCmd)
+	installCmd.Flags().IntVarP(&jobs, "jobs", "j", packages.DefaultJobs, "number of concurrent downloads")
 }
@@ -2,10 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
 	"github.com/spf13/cobra"
-	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/packages"
 )
 
 // listCmd represents the list command
@@ -13,7 +12,18 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List Installed Packages",
 	Aliases: []string{"ls"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if structured() {
+			installed, err := client.List()
+			if err != nil {
+				return err
+			}
+			var rs []record
+			for _, p := range installed {
+				rs = append(rs, record{Package: p.Name, Category: p.Category, Installed: p.Version, Latest: p.Latest, Classpath: client.Classpath(), Action: actionNone})
+			}
+			return printRecords(rs)
+		}
 
 		// Collect installed packages
 		var installed packages.Packages
@@ -27,11 +37,12 @@ var listCmd = &cobra.Command{
 		// Format output
 		fmt.Println(app.Classpath)
 		if len(installed) == 0 {
-			os.Exit(0)
+			return nil
 		}
 		for _, out := range installed.Display(app.ClasspathFiles) {
 			fmt.Println(out)
 		}
+		return nil
 	},
 }
 
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/dependencies"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"strconv"
+)
+
+var failOutdated bool
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List Outdated Packages in the Local and Global Classpaths",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := dependencies.Dependencies{}
+		if err := d.Read(); err != nil {
+			return err
+		}
+
+		// Local packages may be held back by liquibase.json, global packages want the latest version
+		rs := outdatedRecords(app.Classpath, app.ClasspathFiles, d)
+		if app.Classpath != globalpath {
+			rs = append(rs, outdatedRecords(globalpath, globalpathFiles, dependencies.Dependencies{})...)
+		}
+
+		if structured() {
+			if err := printRecords(rs); err != nil {
+				return err
+			}
+		} else if len(rs) == 0 {
+			fmt.Println("You have no outdated packages installed.")
+		} else {
+			var prefix string
+			fmt.Println("You have " + strconv.Itoa(len(rs)) + " outdated package(s) installed.")
+			for i, r := range rs {
+				if i == 0 || rs[i-1].Classpath != r.Classpath {
+					fmt.Println(r.Classpath)
+					fmt.Printf("%-4s %-38s %-20s %-20s %s\n", "   ", "Package", "Installed", "Wanted", "Latest")
+				}
+				if (i+1) == len(rs) || rs[i+1].Classpath != r.Classpath {
+					prefix = "└──"
+				} else {
+					prefix = "├──"
+				}
+				fmt.Printf("%-4s %-38s %-20s %-20s %s\n", prefix, r.Package, r.Installed, r.Wanted, r.Latest)
+			}
+		}
+		if failOutdated && len(rs) > 0 {
+			return errors.WithCode(fmt.Errorf("%d outdated package(s) found.", len(rs)), 1)
+		}
+		return nil
+	},
+}
+
+// outdatedRecords outdated packages in classpath with the newest version allowed by liquibase.json
+func outdatedRecords(cp string, files []fs.FileInfo, d dependencies.Dependencies) []record {
+	var rs []record
+	for _, p := range packs.GetOutdated(liquibase.Version, files) {
+		latest := p.GetLatestVersion(liquibase.Version)
+		wanted := latest
+		if c := d.Get(p.Name).GetVersion(); c != "" {
+			wanted, _ = p.ResolveVersion(c, liquibase.Version)
+		}
+		if wanted.Tag == "" {
+			wanted.Tag = "-"
+		}
+		rs = append(rs, record{
+			Package:   p.Name,
+			Category:  p.Category,
+			Installed: p.GetInstalledVersion(files).Tag,
+			Wanted:    wanted.Tag,
+			Latest:    latest.Tag,
+			Classpath: cp,
+			Action:    actionOutdated,
+		})
+	}
+	return rs
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().BoolVar(&failOutdated, "fail", false, "exit with status 1 when any package is outdated")
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// Output formats selected with --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var output string
+
+// record structured output for a single package
+type record struct {
+	Package   string `json:"package" yaml:"package"`
+	Category  string `json:"category" yaml:"category"`
+	Installed string `json:"installed" yaml:"installed"`
+	Wanted    string `json:"wanted,omitempty" yaml:"wanted,omitempty"`
+	Latest    string `json:"latest,omitempty" yaml:"latest,omitempty"`
+	Classpath string `json:"classpath" yaml:"classpath"`
+	Registry  string `json:"registry,omitempty" yaml:"registry,omitempty"`
+	Action    string `json:"action" yaml:"action"`
+}
+
+// Actions reported in records
+const (
+	actionNone         = "none"
+	actionOutdated     = "outdated"
+	actionUpgraded     = "upgraded"
+	actionWouldUpgrade = "would-upgrade"
+	actionRemoved      = "removed"
+	actionWouldRemove  = "would-remove"
+	actionKept         = "kept"
+	actionVerified     = "verified"
+	actionMismatch     = "checksum-mismatch"
+	actionCorrupt      = "corrupt"
+	actionWeakChecksum = "weak-checksum"
+)
+
+// structured output requested instead of text
+func structured() bool {
+	return output != outputText
+}
+
+func validateOutput() error {
+	switch output {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("Invalid output format '%s', expected text, json or yaml.", output)
+}
+
+// printRecords write records to stdout in the selected structured format
+func printRecords(rs []record) error {
+	if rs == nil {
+		rs = []record{}
+	}
+	return printStructured(rs)
+}
+
+// printStructured write value to stdout in the selected structured format
+func printStructured(v any) error {
+	if output == outputYAML {
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", " ")
+	return enc.Encode(v)
+}
@@ -3,9 +3,6 @@ package commands
 import (
 	"fmt"
 	"github.com/spf13/cobra"
-	"package-manager/internal/app"
-	"package-manager/internal/app/dependencies"
-	"package-manager/internal/app/errors"
 )
 
 // removeCmd represents the install command
@@ -14,35 +11,14 @@ var removeCmd = &cobra.Command{
 	Short:   "Removes Package",
 	Aliases: []string{"rm"},
 	Args:    cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-
-		d := dependencies.Dependencies{}
-		if !global {
-			d.Read()
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Remove Each Package
-		for _, name := range args {
-			p := packs.GetByName(name)
-			v := p.GetInstalledVersion(app.ClasspathFiles)
-			if p.Name == "" {
-				errors.Exit("Package '"+name+"' not found.", 1)
-			}
-			if !v.InClassPath(app.ClasspathFiles) {
-				errors.Exit(name+" is not installed.", 1)
-			}
-			err := p.Remove(app.Classpath, v)
-			if err != nil {
-				errors.Exit("Unable to remove "+v.GetFilename()+" from classpath.", 1)
-			}
-			fmt.Println(v.GetFilename() + " successfully uninstalled from classpath.")
-			if !global {
-				d.Remove(p.Name)
-			}
-		}
-		if !global {
-			d.Write()
+		res, err := client.Remove(args...)
+		for _, r := range res.Removed {
+			fmt.Println(r.Filename + " successfully uninstalled from classpath.")
 		}
+		return err
 	},
 }
 
@@ -1,14 +1,25 @@
 package commands
 
 import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/config"
+	"github.com/liquibase/liquibase-package-manager/internal/app/dependencies"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"github.com/liquibase/liquibase-package-manager/pkg/lpm"
 	"github.com/spf13/cobra"
-	"io"
 	"io/fs"
 	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/errors"
-	"package-manager/internal/app/packages"
-	"package-manager/internal/app/utils"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
 var (
@@ -20,58 +31,283 @@ var (
 	global          bool
 	dryRun          bool
 	skipExisting    bool
+	replace         bool
+	offline         bool
+	minAlgorithm    string
+	keyring         string
+	registries      []string
+	insecureRegs    []string
+	trustedKeys     []string
+	classpath       string
+	auth            []utils.Credential
+	insecureAuth    bool
+	proxy           string
+	connectTimeout  time.Duration
+	readTimeout     time.Duration
+	retries         int
+	jobs            int
+	client          *lpm.Client
+	libErr          error // lib directory of the Liquibase home could not be read
 )
 
+// conf layered settings from config files and the environment, flags override them
+var conf, confErr = config.Load()
+
+// KeyringFile default keyring in the global lib directory, exported for overwrite
+var KeyringFile = "lpm-keyring.asc"
+
+// HomeSource how the Liquibase home was located, exported for overwrite by cmd/lpm
+var HomeSource = "LIQUIBASE_HOME"
+
 var rootCmd = &cobra.Command{
 	Use:   "lpm",
 	Short: "Liquibase Package Manager",
 	Long: `Easily manage external dependencies for Database Development.
 Search for, install, and uninstall liquibase drivers, extensions, and utilities.`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := preRun(cmd); err != nil {
+			return err
+		}
+		if libErr != nil {
+			return libErr
+		}
+		return initConfig(cmd.Context())
+	},
+}
+
+// preRun checks shared by every command once its arguments are valid: the output format and the config files
+func preRun(cmd *cobra.Command) error {
+	// Arguments are valid from here on, failures should not print usage
+	cmd.SilenceUsage = true
+	if err := validateOutput(); err != nil {
+		return err
+	}
+	return confErr
 }
 
 // Execute main entry point for CLI
 func Execute(cp string, s string) {
-	var err error
 	liquibase = utils.LoadLiquibase(cp)
 	globalpath = liquibase.Homepath + "lib" + s
-	globalpathFiles, err = utils.ReadDir(globalpath)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
-	if err := rootCmd.Execute(); err != nil {
-		errors.Exit(err.Error(), 1)
+	globalpathFiles, libErr = utils.ReadDir(globalpath)
+	// Cancel in-flight downloads on SIGINT so partial files are cleaned up, a second signal exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if structured() {
+			// Keep stdout parseable
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(errors.Code(err))
+		}
+		errors.Exit(err.Error(), errors.Code(err))
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
 	//Global params
 	//rootCmd.CompletionOptions.DisableDefaultCmd = true
-	rootCmd.PersistentFlags().StringVar(&category, "category", "", "extension, driver, or utility")
+	rootCmd.PersistentFlags().StringVar(&category, "category", conf.String("category", ""), "extension, driver, or utility")
+	rootCmd.PersistentFlags().DurationVar(&connectTimeout, "connect-timeout", utils.DefaultHTTP.ConnectTimeout, "timeout for establishing connections")
+	rootCmd.PersistentFlags().DurationVar(&readTimeout, "read-timeout", utils.DefaultHTTP.ReadTimeout, "timeout waiting for response data")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", utils.DefaultHTTP.Retries, "retries on server errors and connection failures")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", conf.String("proxy", ""), "HTTP proxy URL, defaults to HTTPS_PROXY and HTTP_PROXY (env LPM_PROXY)")
+	rootCmd.PersistentFlags().StringVar(&classpath, "classpath", "", "local classpath directory, overrides the liquibase.json classpath field (default liquibase_libs, env LPM_CLASSPATH)")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", conf.String("output", outputText), "output format: text, json or yaml")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", confBool("offline"), "resolve packages only from local paths and the download cache (env LPM_OFFLINE)")
+	rootCmd.PersistentFlags().StringSliceVar(&registries, "registry", conf.List("registries"), "additional packages.json URL or path, in priority order before lib/packages.json (env LPM_REGISTRIES)")
+	rootCmd.PersistentFlags().StringSliceVar(&insecureRegs, "insecure-registry", conf.List("insecure-registries"), "registry used without a valid signature (env LPM_INSECURE_REGISTRIES)")
+	rootCmd.PersistentFlags().StringSliceVar(&trustedKeys, "trusted-key", conf.List("trusted-keys"), "base64 ed25519 public key trusted to sign manifests and registries (env LPM_MANIFEST_KEYS)")
+	rootCmd.PersistentFlags().BoolVar(&insecureAuth, "insecure-auth", confBool("insecure-auth"), "also send repository credentials over plain http (env LPM_INSECURE_AUTH)")
+	rootCmd.PersistentFlags().StringVar(&keyring, "keyring", conf.String("keyring", ""), "OpenPGP public keys trusted for package signatures in addition to the embedded keys, unsigned packages are rejected when set, defaults to lib/"+KeyringFile+" (env LPM_KEYRING)")
+	rootCmd.PersistentFlags().StringVar(&minAlgorithm, "min-algorithm", conf.String("min-algorithm", "SHA1"), "weakest checksum algorithm accepted: SHA1, SHA256 or SHA512 (env LPM_MIN_ALGORITHM)")
 	rootCmd.Version = app.Version()
 	rootCmd.SetVersionTemplate("{{with .Name}}{{printf \"%s \" .}}{{end}}{{with .Short}}{{printf \"(%s) \" .}}{{end}}{{printf \"version %s\" .Version}}\n")
 }
 
-func initConfig() {
+func initConfig(ctx context.Context) error {
+	if err := utils.ValidateProxy(proxy); err != nil {
+		return err
+	}
+	if err := localClasspath(); err != nil {
+		return err
+	}
+	if utils.Strength(minAlgorithm) == 0 {
+		return errors.Newf(errors.ErrUnknownAlgorithm, "unknown algorithm %s, expected SHA1, SHA256 or SHA512", minAlgorithm)
+	}
+	var err error
+	if auth, err = loadCredentials(); err != nil {
+		return err
+	}
+
 	//Install Embedded Package File
 	if !app.PackagesInClassPath(globalpath) {
-		app.CopyPackagesToClassPath(globalpath, app.PackagesJSON)
+		if err := app.CopyPackagesToClassPath(globalpath, app.PackagesJSON); err != nil {
+			return err
+		}
 	}
 
 	//Get Bytes from Package File
-	jsonFile, err := os.Open(globalpath + app.PackageFile)
+	b, err := os.ReadFile(globalpath + app.PackageFile)
 	if err != nil {
-		errors.Exit(err.Error(), 1)
+		return err
 	}
-	b, _ := io.ReadAll(jsonFile)
 
 	//Load Bytes to Packages
-	packs = app.LoadPackages(b)
+	packs, err = app.LoadPackages(b)
+	if err != nil {
+		return err
+	}
+	// Configured registries take precedence over the public manifest
+	keys, err := manifestKeys()
+	if err != nil {
+		return err
+	}
+	extra, err := app.Registries{
+		Sources:  registries,
+		Insecure: insecureRegs,
+		Keys:     keys,
+		HTTP:     httpUtil(),
+		Dir:      globalpath + app.RegistryDir,
+	}.Load(ctx)
+	if err != nil {
+		return err
+	}
+	packs = extra.Merge(packs.WithRegistry(globalpath + app.PackageFile))
 	if category != "" {
 		packs = packs.FilterByCategory(category)
 	}
 
 	// Set global vs local classpath
-	app.SetClasspath(global, globalpath, globalpathFiles)
+	if err = app.SetClasspath(global, globalpath, globalpathFiles); err != nil {
+		return err
+	}
+	// Zero retries means the default to lpm.Options
+	r := retries
+	if r == 0 {
+		r = -1
+	}
+	client, err = lpm.NewContext(ctx, lpm.Options{
+		Home:               liquibase.Homepath,
+		Classpath:          app.Classpath,
+		Global:             global,
+		Category:           category,
+		Jobs:               jobs,
+		Progress:           os.Stderr,
+		SkipExisting:       skipExisting,
+		Replace:            replace,
+		Registries:         registries,
+		Offline:            offline,
+		ConnectTimeout:     connectTimeout,
+		ReadTimeout:        readTimeout,
+		Retries:            r,
+		Proxy:              proxy,
+		Credentials:        auth,
+		InsecureAuth:       insecureAuth,
+		CacheDir:           conf.String("cache-dir", ""),
+		MinAlgorithm:       minAlgorithm,
+		Keyring:            keyringFile(),
+		TrustedKeys:        trustedKeys,
+		InsecureRegistries: insecureRegs,
+	})
+	return err
+}
+
+// manifestKeys embedded and --trusted-key keys trusted to sign manifests and registries
+func manifestKeys() ([]ed25519.PublicKey, error) {
+	return app.ParseManifestKeys(app.ManifestKeys + "\n" + strings.Join(trustedKeys, "\n"))
+}
+
+// httpUtil network settings of the command line
+func httpUtil() utils.HTTPUtil {
+	h := utils.DefaultHTTP
+	h.ConnectTimeout, h.ReadTimeout, h.Retries = connectTimeout, readTimeout, retries
+	h.Offline, h.Proxy, h.Credentials, h.InsecureAuth = offline, proxy, auth, insecureAuth
+	return h
+}
+
+// localClasspath set the local classpath directory, in order of precedence: --classpath, LPM_CLASSPATH,
+// the liquibase.json classpath field, then config files. Global commands do not use it.
+func localClasspath() error {
+	if global {
+		return nil
+	}
+	if classpath != "" {
+		app.LocalClasspath = classpath
+		return nil
+	}
+	if v := conf["classpath"]; v.Source == config.SourceEnv {
+		app.LocalClasspath = v.Value
+		return nil
+	}
+	d := dependencies.Dependencies{}
+	if err := d.Read(); err != nil {
+		return err
+	}
+	if d.Classpath != "" {
+		app.LocalClasspath = d.Classpath
+		return nil
+	}
+	app.LocalClasspath = conf.String("classpath", app.LocalClasspath)
+	return nil
+}
+
+// trustedPackageKeys embedded and keyringFile keys trusted for package signatures, unreadable keyrings have none
+func trustedPackageKeys() openpgp.EntityList {
+	kr, _ := packages.ReadKeyring(app.PackageKeys)
+	if path := keyringFile(); path != "" {
+		extra, _ := packages.LoadKeyring(path)
+		kr = append(kr, extra...)
+	}
+	return kr
+}
+
+// keyringFile trusted signing keys from --keyring or the default keyring when it exists, empty when there are none
+func keyringFile() string {
+	if keyring != "" {
+		return keyring
+	}
+	if _, err := os.Stat(globalpath + KeyringFile); err != nil {
+		return ""
+	}
+	return globalpath + KeyringFile
+}
+
+// loadCredentials repository credentials from LPM_AUTH followed by the .netrc file when it exists.
+// There is no flag, secrets on the command line are visible to other processes.
+func loadCredentials() ([]utils.Credential, error) {
+	var cs []utils.Credential
+	for i, a := range envList("LPM_AUTH") {
+		c, err := utils.ParseCredential(a)
+		if err != nil {
+			return nil, fmt.Errorf("LPM_AUTH entry %d: %w", i+1, err)
+		}
+		cs = append(cs, c)
+	}
+	if path := utils.NetrcPath(); path != "" {
+		netrc, err := utils.ReadNetrc(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		cs = append(cs, netrc...)
+	}
+	return cs, nil
+}
+
+// confBool read boolean setting, false when unset or invalid
+func confBool(k string) bool {
+	b, _ := strconv.ParseBool(conf.String(k, ""))
+	return b
+}
+
+// envList read comma separated environment variable, nil when unset
+func envList(k string) []string {
+	if v := os.Getenv(k); v != "" {
+		return strings.Split(v, ",")
+	}
+	return nil
 }
@@ -2,10 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
 	"github.com/spf13/cobra"
-	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/packages"
 	"strings"
 )
 
@@ -13,17 +13,20 @@ import (
 var searchCmd = &cobra.Command{
 	Use:   "search [PACKAGE]",
 	Short: "Search for Packages",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if len(args) > 0 && len(args[0]) < 3 {
+			return fmt.Errorf("Minimum of 3 characters required for search.")
+		}
+		return nil
+	},
 
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var name string
 		if len(args) > 0 {
 			name = args[0]
-			if len(name) < 3 {
-				fmt.Println("Minimum of 3 characters required for search.")
-				os.Exit(1)
-			}
-		} else {
-			name = ""
 		}
 		var found packages.Packages
 		for _, p := range packs {
@@ -32,12 +35,31 @@ var searchCmd = &cobra.Command{
 			}
 		}
 		if len(found) == 0 {
-			fmt.Println("No results found.")
-			os.Exit(1)
+			return errors.New(errors.ErrPackageNotFound, "No results found.")
+		}
+		if structured() {
+			var rs []record
+			for _, p := range found {
+				rs = append(rs, record{
+					Package:   p.Name,
+					Category:  p.Category,
+					Installed: p.GetInstalledVersion(app.ClasspathFiles).Tag,
+					Latest:    p.GetLatestVersion(liquibase.Version).Tag,
+					Classpath: app.Classpath,
+					Registry:  p.Registry,
+					Action:    actionNone,
+				})
+			}
+			return printRecords(rs)
+		}
+		display := found.Display
+		if len(registries) > 0 {
+			display = found.DisplayRegistries
 		}
-		for _, out := range found.Display(app.ClasspathFiles) {
+		for _, out := range display(app.ClasspathFiles) {
 			fmt.Println(out)
 		}
+		return nil
 	},
 }
 
@@ -1,20 +1,21 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
 	"github.com/spf13/cobra"
-	"io"
 	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/errors"
-	"package-manager/internal/app/packages"
-	"package-manager/internal/app/utils"
-	"reflect"
 	"strings"
 )
 
+// ManifestURL default location of the public package manifest
+const ManifestURL = "https://raw.githubusercontent.com/liquibase/liquibase-package-manager/main/internal/app/packages.json"
+
 var (
-	path string
+	path     string
+	insecure bool
 )
 
 // updateCmd represents the update command
@@ -22,42 +23,78 @@ var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Updates the Package Manifest",
 
-	Run: func(cmd *cobra.Command, args []string) {
-		var bytes []byte
-		if strings.HasPrefix(path, "http") {
-			// Update Package from Remote URL
-			bytes = utils.HTTPUtil{}.Get(path)
-		} else {
-			// Update Packages from Local File
-			file, err := os.Open(path)
-			if err != nil {
-				errors.Exit(err.Error(), 1)
-			}
-			bytes, err = io.ReadAll(file)
-			if err != nil {
-				errors.Exit(err.Error(), 1)
-			}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bytes, err := readSource(cmd.Context(), path)
+		if err != nil {
+			return err
+		}
+		// Only parse signed manifests
+		if insecure {
+			fmt.Fprintln(os.Stderr, "WARNING: skipping signature verification of "+path)
+		} else if err = verifyManifest(cmd.Context(), bytes); err != nil {
+			return err
 		}
 		//Verify bytes are valid
-		p := app.LoadPackages(bytes)
-		if reflect.TypeOf(p) != reflect.TypeOf(packages.Packages{}) {
-			errors.Exit("Unable to validate package contents.", 1)
+		p, err := app.LoadPackages(bytes)
+		if err != nil {
+			return fmt.Errorf("Unable to validate package contents.")
 		}
 		if p.GetByName("postgres").Name == "postgres" {
-			errors.Exit("Unable to validate package contents.", 1)
+			return fmt.Errorf("Unable to validate package contents.")
+		}
+		if err = app.CopyPackagesToClassPath(globalpath, bytes); err != nil {
+			return err
 		}
-		app.CopyPackagesToClassPath(globalpath, bytes)
 		fmt.Println("Package manifest updated from " + path)
+		if err = client.UpdateRegistries(cmd.Context()); err != nil {
+			return err
+		}
+		for _, r := range registries {
+			if strings.HasPrefix(r, "http") {
+				fmt.Println("Registry updated from " + r)
+			}
+		}
+		return nil
 	},
 }
 
+// readSource contents of a remote URL, file:// URL or local path, downloading until ctx is done
+func readSource(ctx context.Context, p string) ([]byte, error) {
+	if strings.HasPrefix(p, "http") {
+		if offline {
+			return nil, errors.New(errors.ErrOffline, "Unable to update the package manifest from "+p+" in offline mode. Use --path with a local manifest.")
+		}
+		// Update Package from Remote URL
+		return httpUtil().Read(ctx, p)
+	}
+	// Update Packages from Local File
+	return os.ReadFile(strings.TrimPrefix(p, "file://"))
+}
+
+// verifyManifest check the detached signature next to the manifest against the embedded and configured keys
+func verifyManifest(ctx context.Context, manifest []byte) error {
+	keys, err := manifestKeys()
+	if err != nil {
+		return err
+	}
+	sig, err := readSource(ctx, path+app.SignatureSuffix)
+	if err != nil {
+		return errors.Newf(errors.ErrBadSignature, "Unable to read manifest signature %s: %w\nUse --insecure to update from an unsigned manifest.", path+app.SignatureSuffix, err)
+	}
+	if err = app.VerifyManifest(manifest, sig, keys); err != nil {
+		return errors.Newf(errors.ErrBadSignature, "%w\nUse --insecure to update from an unsigned manifest.", err)
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(updateCmd)
 	updateCmd.Flags().StringVarP(
 		&path,
 		"path",
 		"p",
-		"https://raw.githubusercontent.com/liquibase/liquibase-package-manager/main/internal/app/packages.json",
-		"path to new packages.json manifest",
+		conf.String("manifest-url", ManifestURL),
+		"path to new packages.json manifest (config manifest-url)",
 	)
+	updateCmd.Flags().BoolVar(&insecure, "insecure", false, "update from a manifest without a valid signature")
 }
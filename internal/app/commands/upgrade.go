@@ -2,82 +2,119 @@ package commands
 
 import (
 	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/pkg/lpm"
 	"github.com/spf13/cobra"
-	"os"
-	"package-manager/internal/app"
-	"package-manager/internal/app/dependencies"
-	"package-manager/internal/app/errors"
 	"strconv"
 )
 
+// Upgrade selection flags
+var (
+	upgradeTo    string
+	upgradePatch bool
+	upgradeMinor bool
+	upgradeMajor bool
+)
+
 // upgradeCmd represents the update command
 var upgradeCmd = &cobra.Command{
 	Use:     "upgrade [PACKAGE]...",
 	Short:   "Upgrades Installed Packages to the Latest Versions",
 	Aliases: []string{"up"},
 
-	Run: func(cmd *cobra.Command, args []string) {
-		outdated := packs.GetOutdated(liquibase.Version, app.ClasspathFiles)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := upgradeOptions(args)
+		outdated, err := client.PlanUpgrade(opts)
+		if err != nil {
+			return err
+		}
+		if structured() {
+			return upgradeRecords(cmd, opts, outdated)
+		}
 		if len(outdated) == 0 {
 			fmt.Println("You have no outdated packages installed.")
 			fmt.Println(app.Classpath)
-			os.Exit(0)
+			return nil
 		}
 		var r []string
 		var prefix string
 		r = append(r, fmt.Sprintf("%-4s %-38s %-38s %s", "   ", "Package", "Installed", "Available"))
 		for i, p := range outdated {
-			ins := p.GetInstalledVersion(app.ClasspathFiles)
-			latest := p.GetLatestVersion(liquibase.Version)
 			if (i + 1) == len(outdated) {
 				prefix = "└──"
 			} else {
 				prefix = "├──"
 			}
-			r = append(r, fmt.Sprintf("%-4s %-38s %-38s %s", prefix, p.Name, ins.Tag, latest.Tag))
+			r = append(r, fmt.Sprintf("%-4s %-38s %-38s %s", prefix, p.Name, p.Installed, p.Target))
 		}
 		fmt.Println("You have " + strconv.Itoa(len(outdated)) + " outdated package(s) installed.")
 		fmt.Println(app.Classpath)
 		for _, out := range r {
 			fmt.Println(out)
 		}
-		if !dryRun {
-			d := dependencies.Dependencies{}
-			if !global {
-				d.Read()
-			}
-			for _, p := range outdated {
-				ins := p.GetInstalledVersion(app.ClasspathFiles)
-				latest := p.GetLatestVersion(liquibase.Version)
-				fmt.Println()
-				fmt.Println("removing " + p.Name + "@" + ins.Tag + " from classpath")
-				err := p.Remove(app.Classpath, ins)
-				if err != nil {
-					errors.Exit("Unable to remove "+ins.GetFilename()+" from classpath.", 1)
-				}
-				fmt.Println(ins.GetFilename() + " successfully uninstalled from classpath.")
-				if !global {
-					d.Remove(p.Name)
-				}
-				fmt.Println()
-				fmt.Println("adding " + p.Name + "@" + latest.Tag + " to classpath")
-				if !latest.PathIsHTTP() {
-					latest.CopyToClassPath(app.Classpath)
-				} else {
-					latest.DownloadToClassPath(app.Classpath)
-				}
-				fmt.Println(latest.GetFilename() + " successfully installed in classpath.")
-				d.Dependencies = append(d.Dependencies, dependencies.Dependency{p.Name: latest.Tag})
-			}
-			if !global {
-				d.Write()
-			}
+		if dryRun {
+			return nil
+		}
+
+		// Fetch every upgrade before touching the installed versions
+		for _, p := range outdated {
+			fmt.Println("adding " + p.Name + "@" + p.Target + " to classpath")
+		}
+		res, err := client.UpgradeWith(cmd.Context(), opts)
+		for _, ins := range res.Installed {
+			fmt.Println(ins.Filename + " successfully installed in classpath.")
 		}
+		printUnverified(res.Unverified)
+		for _, rm := range res.Removed {
+			fmt.Println()
+			fmt.Println("removing " + rm.Name + "@" + rm.Version + " from classpath")
+			fmt.Println(rm.Filename + " successfully uninstalled from classpath.")
+		}
+		return err
 	},
 }
 
+// upgradeOptions packages and version bounds selected on the command line
+func upgradeOptions(args []string) lpm.UpgradeOptions {
+	opts := lpm.UpgradeOptions{Packages: args, To: upgradeTo}
+	switch {
+	case upgradePatch:
+		opts.Policy = lpm.Patch
+	case upgradeMinor:
+		opts.Policy = lpm.Minor
+	case upgradeMajor:
+		opts.Policy = lpm.Major
+	case upgradeTo == "":
+		opts.Policy = conf.String("upgrade-policy", "")
+	}
+	return opts
+}
+
+// upgradeRecords upgrade outdated packages reporting the structured result
+func upgradeRecords(cmd *cobra.Command, opts lpm.UpgradeOptions, outdated []lpm.OutdatedPackage) error {
+	action := actionWouldUpgrade
+	if !dryRun && len(outdated) > 0 {
+		if _, err := client.UpgradeWith(cmd.Context(), opts); err != nil {
+			return err
+		}
+		action = actionUpgraded
+	}
+	var rs []record
+	for _, p := range outdated {
+		rs = append(rs, record{Package: p.Name, Category: p.Category, Installed: p.Installed, Wanted: p.Target, Latest: p.Latest, Classpath: client.Classpath(), Action: action})
+	}
+	return printRecords(rs)
+}
+
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
 	upgradeCmd.Flags().BoolVarP(&global, "global", "g", false, "upgrade global packages")
+	upgradeCmd.Flags().IntVarP(&jobs, "jobs", "j", packages.DefaultJobs, "number of concurrent downloads")
 	upgradeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "output changes without applying")
+	upgradeCmd.Flags().StringVar(&upgradeTo, "to", "", "upgrade a single package to this version or range")
+	upgradeCmd.Flags().BoolVar(&upgradePatch, "patch", false, "only upgrade to newer patch versions")
+	upgradeCmd.Flags().BoolVar(&upgradeMinor, "minor", false, "only upgrade to newer minor or patch versions")
+	upgradeCmd.Flags().BoolVar(&upgradeMajor, "major", false, "upgrade to any newer version (default)")
+	upgradeCmd.MarkFlagsMutuallyExclusive("to", "patch", "minor", "major")
 }
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"strconv"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify Checksums of Installed Packages",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rs := verifyRecords(app.Classpath, app.ClasspathFiles)
+		if app.Classpath != globalpath {
+			rs = append(rs, verifyRecords(globalpath, globalpathFiles)...)
+		}
+
+		var failed int
+		for _, r := range rs {
+			if r.Action != actionVerified {
+				failed++
+			}
+		}
+		if structured() {
+			if err := printRecords(rs); err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return errors.New(errors.ErrChecksumMismatch, strconv.Itoa(failed)+" of "+strconv.Itoa(len(rs))+" installed package(s) failed verification.")
+		}
+		if !structured() {
+			fmt.Println(strconv.Itoa(len(rs)) + " installed package(s) verified.")
+		}
+		return nil
+	},
+}
+
+// verifyRecords re-hash every managed version installed in classpath
+func verifyRecords(cp string, files []fs.FileInfo) []record {
+	var rs []record
+	for _, p := range packs {
+		for _, v := range p.Versions {
+			if !v.InClassPath(files) {
+				continue
+			}
+			r := record{Package: p.Name, Category: p.Category, Installed: v.Tag, Classpath: cp, Action: actionVerified}
+			err := v.Verify(cp, packages.Options{MinAlgorithm: minAlgorithm})
+			switch {
+			case err == nil:
+				if !structured() {
+					fmt.Println(cp + v.GetFilename() + " verified.")
+				}
+			case errors.Is(err, errors.ErrCorrupt):
+				r.Action = actionCorrupt
+			case errors.Is(err, errors.ErrWeakChecksum):
+				r.Action = actionWeakChecksum
+			default:
+				r.Action = actionMismatch
+			}
+			if err != nil && !structured() {
+				fmt.Println(cp + v.GetFilename() + " failed verification: " + err.Error())
+			}
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Setting configurable key, its environment variable overrides config files
+type Setting struct {
+	Key   string
+	Env   string
+	List  bool // comma separated in the environment, a sequence in config files
+	Usage string
+	Check func(string) error // validates a single value, every value is accepted when nil
+}
+
+// Settings recognised in config files
+var Settings = []Setting{
+	{Key: "cache-dir", Env: "LPM_CACHE_DIR", Usage: "download cache directory"},
+	{Key: "category", Env: "LPM_CATEGORY", Usage: "only consider packages of category: extension, driver or utility", Check: oneOf("extension", "driver", "utility")},
+	{Key: "classpath", Env: "LPM_CLASSPATH", Usage: "local classpath directory, relative to the project"},
+	{Key: "insecure-auth", Env: "LPM_INSECURE_AUTH", Usage: "also send repository credentials over plain http", Check: checkBool},
+	{Key: "insecure-registries", Env: "LPM_INSECURE_REGISTRIES", List: true, Usage: "registries used without a valid signature"},
+	{Key: "keyring", Env: "LPM_KEYRING", Usage: "OpenPGP public keys trusted for package signatures"},
+	{Key: "manifest-url", Env: "LPM_MANIFEST_URL", Usage: "packages.json location used by update"},
+	{Key: "min-algorithm", Env: "LPM_MIN_ALGORITHM", Usage: "weakest checksum algorithm accepted: SHA1, SHA256 or SHA512", Check: checkAlgorithm},
+	{Key: "offline", Env: "LPM_OFFLINE", Usage: "resolve packages only from local paths and the download cache", Check: checkBool},
+	{Key: "output", Env: "LPM_OUTPUT", Usage: "default output format: text, json or yaml", Check: oneOf("text", "json", "yaml")},
+	{Key: "proxy", Env: "LPM_PROXY", Usage: "HTTP proxy URL, defaults to HTTPS_PROXY and HTTP_PROXY", Check: utils.ValidateProxy},
+	{Key: "registries", Env: "LPM_REGISTRIES", List: true, Usage: "additional packages.json locations in priority order"},
+	{Key: "trusted-keys", Env: "LPM_MANIFEST_KEYS", List: true, Usage: "base64 ed25519 public keys trusted to sign manifests", Check: checkKey},
+	{Key: "upgrade-policy", Env: "LPM_UPGRADE_POLICY", Usage: "default upgrade policy: patch, minor or major", Check: oneOf("patch", "minor", "major")},
+}
+
+// Sources of a setting value, from lowest to highest precedence after defaults
+const (
+	SourceUser    = "user"
+	SourceProject = "project"
+	SourceEnv     = "env"
+)
+
+// UserFile user level config file, exported for overwrite
+var UserFile string
+
+// ProjectFile project level config file in the working directory, exported for overwrite
+var ProjectFile = ".lpmrc"
+
+func init() {
+	d := os.Getenv("XDG_CONFIG_HOME")
+	if d == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return
+		}
+		d = filepath.Join(home, ".config")
+	}
+	UserFile = filepath.Join(d, "lpm", "config.yaml")
+}
+
+// Lookup setting by key
+func Lookup(key string) (Setting, error) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown config key %s", key)
+}
+
+// Validate check value for the setting, each item of a list setting separately
+func (s Setting) Validate(value string) error {
+	if s.Check == nil {
+		return nil
+	}
+	items := []string{value}
+	if s.List {
+		items = strings.Split(value, ",")
+	}
+	for _, i := range items {
+		if err := s.Check(i); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", s.Key, err)
+		}
+	}
+	return nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		for _, e := range values {
+			if v == e {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s, expected %s", v, strings.Join(values, ", "))
+	}
+}
+
+func checkAlgorithm(v string) error {
+	if utils.Strength(v) == 0 {
+		return fmt.Errorf("unknown algorithm %s, expected SHA1, SHA256 or SHA512", v)
+	}
+	return nil
+}
+
+func checkBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("%s, expected true or false", v)
+	}
+	return nil
+}
+
+func checkKey(v string) error {
+	if k, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v)); err != nil || len(k) != ed25519.PublicKeySize {
+		return fmt.Errorf("%s is not a base64 ed25519 public key", v)
+	}
+	return nil
+}
+
+// File settings of a single config file, list values are comma separated
+type File map[string]string
+
+// Read config file, a missing file has no settings. Unknown keys and invalid values are errors.
+func Read(path string) (File, error) {
+	f, err := ReadUnchecked(path)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range f {
+		s, err := Lookup(k)
+		if err == nil {
+			err = s.Validate(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config %s: %w", path, err)
+		}
+	}
+	return f, nil
+}
+
+// ReadUnchecked config file keeping unknown keys and invalid values, so they can be repaired
+func ReadUnchecked(path string) (File, error) {
+	f := File{}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err = yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("unable to read config %s: %w", path, err)
+	}
+	for k, v := range raw {
+		switch v := v.(type) {
+		case nil:
+		case []any:
+			var items []string
+			for _, i := range v {
+				items = append(items, fmt.Sprint(i))
+			}
+			f[k] = strings.Join(items, ",")
+		default:
+			f[k] = fmt.Sprint(v)
+		}
+	}
+	return f, nil
+}
+
+// Write config file, creating its directory. List settings are written as sequences.
+func (f File) Write(path string) error {
+	out := map[string]any{}
+	for k, v := range f {
+		if s, _ := Lookup(k); s.List {
+			out[k] = strings.Split(v, ",")
+		} else {
+			out[k] = v
+		}
+	}
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0775); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0664)
+}
+
+// Value effective setting and the layer it came from
+type Value struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Config merged settings of the user file, project file and environment
+type Config map[string]Value
+
+// Load layered settings, each layer overrides the previous: user file, project file, environment.
+// Every value is validated.
+func Load() (Config, error) {
+	c := Config{}
+	for _, l := range []struct {
+		source string
+		path   string
+	}{{SourceUser, UserFile}, {SourceProject, ProjectFile}} {
+		if l.path == "" {
+			continue
+		}
+		f, err := Read(l.path)
+		if err != nil {
+			return c, err
+		}
+		for k, v := range f {
+			c[k] = Value{Key: k, Value: v, Source: l.source}
+		}
+	}
+	for _, s := range Settings {
+		if v := os.Getenv(s.Env); v != "" {
+			if err := s.Validate(v); err != nil {
+				return c, fmt.Errorf("%s: %w", s.Env, err)
+			}
+			c[s.Key] = Value{Key: s.Key, Value: v, Source: SourceEnv}
+		}
+	}
+	return c, nil
+}
+
+// String setting value, def when not configured
+func (c Config) String(key string, def string) string {
+	if v, ok := c[key]; ok && v.Value != "" {
+		return v.Value
+	}
+	return def
+}
+
+// List setting values, nil when not configured
+func (c Config) List(key string) []string {
+	if v := c.String(key, ""); v != "" {
+		return strings.Split(v, ",")
+	}
+	return nil
+}
+
+// Values configured settings sorted by key
+func (c Config) Values() []Value {
+	var r []Value
+	for _, v := range c {
+		r = append(r, v)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Key < r[j].Key })
+	return r
+}
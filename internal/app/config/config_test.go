@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	user, project := UserFile, ProjectFile
+	UserFile, ProjectFile = filepath.Join(dir, "lpm", "config.yaml"), filepath.Join(dir, ".lpmrc")
+	t.Cleanup(func() { UserFile, ProjectFile = user, project })
+	for _, s := range Settings {
+		t.Setenv(s.Env, "")
+	}
+
+	if err := (File{"output": "json", "classpath": "db/lib", "registries": "a.json,b.json"}).Write(UserFile); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	os.WriteFile(ProjectFile, []byte("classpath: lib\nregistries:\n  - c.json\n"), 0664)
+	t.Setenv("LPM_OUTPUT", "yaml")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Config{
+		"output":     {Key: "output", Value: "yaml", Source: SourceEnv},
+		"classpath":  {Key: "classpath", Value: "lib", Source: SourceProject},
+		"registries": {Key: "registries", Value: "c.json", Source: SourceProject},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Load() = %v, want %v", c, want)
+	}
+	if got := c.List("registries"); !reflect.DeepEqual(got, []string{"c.json"}) {
+		t.Errorf("List() = %v", got)
+	}
+	if got := c.String("proxy", "none"); got != "none" {
+		t.Errorf("String() = %v, want default", got)
+	}
+
+	t.Setenv("LPM_OFFLINE", "maybe")
+	if _, err = Load(); err == nil {
+		t.Errorf("Expected Load() to reject invalid LPM_OFFLINE")
+	}
+	t.Setenv("LPM_OFFLINE", "")
+	os.WriteFile(ProjectFile, []byte("category: foo\n"), 0664)
+	if _, err = Load(); err == nil {
+		t.Errorf("Expected Load() to reject invalid category in %s", ProjectFile)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    File
+		wantErr bool
+	}{
+		{name: "Can Read Settings", content: "offline: true\nregistries: [a.json, b.json]\n", want: File{"offline": "true", "registries": "a.json,b.json"}},
+		{name: "Can Detect Unknown Key", content: "colour: red\n", wantErr: true},
+		{name: "Can Detect Invalid Value", content: "offline: maybe\n", wantErr: true},
+		{name: "Can Detect Invalid YAML", content: "output: [json\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			os.WriteFile(path, []byte(tt.content), 0664)
+			got, err := Read(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if f, err := Read(filepath.Join(dir, "missing.yaml")); err != nil || len(f) != 0 {
+		t.Errorf("Read() missing file = %v, %v", f, err)
+	}
+
+	path := filepath.Join(dir, "broken.yaml")
+	os.WriteFile(path, []byte("colour: red\ncategory: foo\n"), 0664)
+	if f, err := ReadUnchecked(path); err != nil || !reflect.DeepEqual(f, File{"colour": "red", "category": "foo"}) {
+		t.Errorf("ReadUnchecked() = %v, %v", f, err)
+	}
+}
+
+func TestSetting_Validate(t *testing.T) {
+	const key = "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: "output", value: "json"},
+		{key: "output", value: "xml", wantErr: true},
+		{key: "min-algorithm", value: "sha256"},
+		{key: "min-algorithm", value: "MD5", wantErr: true},
+		{key: "upgrade-policy", value: "minor"},
+		{key: "upgrade-policy", value: "latest", wantErr: true},
+		{key: "category", value: "plugin", wantErr: true},
+		{key: "offline", value: "yes", wantErr: true},
+		{key: "proxy", value: "proxy.example.com", wantErr: true},
+		{key: "trusted-keys", value: key + "," + key},
+		{key: "trusted-keys", value: key + ",abc", wantErr: true},
+		{key: "registries", value: "a.json,https://example.com/b.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			s, err := Lookup(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = s.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,56 +2,68 @@ package dependencies
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
-	"package-manager/internal/app/errors"
 )
 
 // FileLocation exported for testing overwrite
 var FileLocation string
 
 func init() {
-	pwd, err := os.Getwd()
-	if err != nil {
-		errors.Exit(err.Error(), 1)
+	FileLocation = "liquibase.json"
+	if pwd, err := os.Getwd(); err == nil {
+		FileLocation = pwd + "/liquibase.json"
 	}
-	FileLocation = pwd + "/liquibase.json"
 }
 
 // Dependencies main wrapper for liquibase.json objects
 type Dependencies struct {
+	Classpath    string       `json:"classpath,omitempty"` // local classpath directory relative to liquibase.json
 	Dependencies []Dependency `json:"dependencies"`
 }
 
 // CreateFile init liquibase.json file in pwd
-func (d Dependencies) CreateFile() {
+func (d Dependencies) CreateFile() error {
 	file, err := os.Create(FileLocation)
 	if err != nil {
-		errors.Exit(err.Error(), 1)
+		return err
 	}
-	defer file.Close()
-	d.Write()
+	file.Close()
+	return d.Write()
 }
 
 // Write dump contents to liquibase.json
-func (d Dependencies) Write() {
+func (d Dependencies) Write() error {
+	return d.WriteFile(FileLocation)
+}
+
+// WriteFile dump contents to the liquibase.json at path
+func (d Dependencies) WriteFile(path string) error {
 	file, err := json.MarshalIndent(d, "", " ")
 	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
-	err = os.WriteFile(FileLocation, file, 0664)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
+		return err
 	}
+	return os.WriteFile(path, file, 0664)
+}
+
+// Read get contents from liquibase.json, a missing file has no dependencies
+func (d *Dependencies) Read() error {
+	return d.ReadFile(FileLocation)
 }
 
-// Read get contents from liquibase.json
-func (d *Dependencies) Read() {
-	file, _ := os.Open(FileLocation)
-	defer file.Close()
-	decoder := json.NewDecoder(file)
-	for decoder.More() {
-		decoder.Decode(d)
+// ReadFile get contents from the liquibase.json at path, a missing file has no dependencies
+func (d *Dependencies) ReadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, d); err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return nil
 }
 
 // FileExists does the liquibase.json file exist
@@ -60,6 +72,16 @@ func (d Dependencies) FileExists() bool {
 	return err == nil
 }
 
+// Get specific dependency from group by name
+func (d Dependencies) Get(n string) Dependency {
+	for _, m := range d.Dependencies {
+		if m.GetName() == n {
+			return m
+		}
+	}
+	return Dependency{}
+}
+
 // Remove remove specific dependency from group
 func (d *Dependencies) Remove(n string) {
 	for i, m := range d.Dependencies {
@@ -0,0 +1,119 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"os"
+	"sort"
+)
+
+// LockFileLocation exported for testing overwrite
+var LockFileLocation string
+
+// LockfileVersion current format of liquibase.lock.json
+const LockfileVersion = 1
+
+func init() {
+	LockFileLocation = "liquibase.lock.json"
+	if pwd, err := os.Getwd(); err == nil {
+		LockFileLocation = pwd + "/liquibase.lock.json"
+	}
+}
+
+// Lockfile main wrapper for liquibase.lock.json objects
+type Lockfile struct {
+	LockfileVersion int             `json:"lockfileVersion"`
+	Packages        []LockedPackage `json:"packages"`
+}
+
+// LockedPackage resolved package version pinned in liquibase.lock.json
+type LockedPackage struct {
+	Name string `json:"name"`
+	packages.Version
+}
+
+// Write dump contents to liquibase.lock.json
+func (l Lockfile) Write() error {
+	return l.WriteFile(LockFileLocation)
+}
+
+// WriteFile dump contents to the liquibase.lock.json at path
+func (l Lockfile) WriteFile(path string) error {
+	l.LockfileVersion = LockfileVersion
+	if l.Packages == nil {
+		l.Packages = []LockedPackage{}
+	}
+	sort.Slice(l.Packages, func(i, j int) bool { return l.Packages[i].Name < l.Packages[j].Name })
+	file, err := json.MarshalIndent(l, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, file, 0664)
+}
+
+// Read get contents from liquibase.lock.json, a missing file has no locked packages
+func (l *Lockfile) Read() error {
+	return l.ReadFile(LockFileLocation)
+}
+
+// ReadFile get contents from the liquibase.lock.json at path, a missing file has no locked packages
+func (l *Lockfile) ReadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, l); err != nil {
+		return fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// FileExists does the liquibase.lock.json file exist
+func (l Lockfile) FileExists() bool {
+	_, err := os.Stat(LockFileLocation)
+	return err == nil
+}
+
+// Get locked version for package name
+func (l Lockfile) Get(n string) (packages.Version, bool) {
+	for _, p := range l.Packages {
+		if p.Name == n {
+			return p.Version, true
+		}
+	}
+	return packages.Version{}, false
+}
+
+// Versions locked versions by package name
+func (l Lockfile) Versions() map[string]packages.Version {
+	r := map[string]packages.Version{}
+	for _, p := range l.Packages {
+		r[p.Name] = p.Version
+	}
+	return r
+}
+
+// Set pin package name to resolved version
+func (l *Lockfile) Set(n string, v packages.Version) {
+	for i, p := range l.Packages {
+		if p.Name == n {
+			l.Packages[i].Version = v
+			return
+		}
+	}
+	l.Packages = append(l.Packages, LockedPackage{Name: n, Version: v})
+}
+
+// Remove remove specific package from lockfile
+func (l *Lockfile) Remove(n string) {
+	for i, p := range l.Packages {
+		if p.Name == n {
+			l.Packages = append(l.Packages[:i], l.Packages[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package dependencies
+
+import (
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var lockedV1 = packages.Version{
+	Tag:           "0.0.1",
+	Path:          "tests/mocks/files/driver-0.0.1.txt",
+	Algorithm:     "SHA1",
+	CheckSum:      "abc",
+	LiquibaseCore: "4.6.2",
+}
+var lockedV2 = packages.Version{
+	Tag:           "0.2.0",
+	Path:          "tests/mocks/files/driver-0.2.0.txt",
+	Algorithm:     "SHA1",
+	CheckSum:      "def",
+	LiquibaseCore: "4.16.2",
+}
+
+func init() {
+	rootPath, _ := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	LockFileLocation = strings.TrimRight(string(rootPath), "\n") + "/tests/mocks/liquibase.lock.json"
+}
+
+func TestLockfile_Set(t *testing.T) {
+	l := Lockfile{}
+	l.Set("driver", lockedV1)
+	l.Set("driver", lockedV2)
+	if len(l.Packages) != 1 {
+		t.Fatalf("Expected 1 locked package but got %d", len(l.Packages))
+	}
+	if got, _ := l.Get("driver"); !reflect.DeepEqual(got, lockedV2) {
+		t.Errorf("Get() = %v, want %v", got, lockedV2)
+	}
+}
+
+func TestLockfile_WriteRead(t *testing.T) {
+	l := Lockfile{}
+	l.Set("zeta", lockedV2)
+	l.Set("driver", lockedV1)
+	l.Write()
+	t.Cleanup(func() {
+		os.Remove(LockFileLocation)
+	})
+
+	if !l.FileExists() {
+		t.Fatalf("Unable to verify liquibase.lock.json file exists.")
+	}
+	ll := Lockfile{}
+	ll.Read()
+	if ll.LockfileVersion != LockfileVersion {
+		t.Errorf("LockfileVersion = %d, want %d", ll.LockfileVersion, LockfileVersion)
+	}
+	if ll.Packages[0].Name != "driver" || ll.Packages[1].Name != "zeta" {
+		t.Errorf("Expected locked packages sorted by name, got %v", ll.Packages)
+	}
+	if got, ok := ll.Get("zeta"); !ok || !reflect.DeepEqual(got, lockedV2) {
+		t.Errorf("Get() = %v, want %v", got, lockedV2)
+	}
+}
+
+func TestLockfile_Remove(t *testing.T) {
+	l := Lockfile{}
+	l.Set("driver", lockedV1)
+	l.Remove("driver")
+	if _, ok := l.Get("driver"); ok {
+		t.Fatalf("Unable to remove locked package")
+	}
+}
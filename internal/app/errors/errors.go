@@ -1,13 +1,103 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
 
+// Typed errors returned throughout lpm, match with Is
+var (
+	// ErrPackageNotFound package or version is not in the manifest
+	ErrPackageNotFound = errors.New("package not found")
+	// ErrIncompatible package version requires a newer liquibase
+	ErrIncompatible = errors.New("incompatible with liquibase")
+	// ErrChecksumMismatch downloaded or installed file does not match the manifest checksum
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrCorrupt installed archive can not be read
+	ErrCorrupt = errors.New("corrupt archive")
+	// ErrBadSignature artifact signature is invalid or not made by a trusted key
+	ErrBadSignature = errors.New("bad signature")
+	// ErrUnsigned artifact signature can not be checked, the version is unsigned or no trusted key is configured
+	ErrUnsigned = errors.New("unsigned")
+	// ErrUnknownAlgorithm checksum algorithm is not supported
+	ErrUnknownAlgorithm = errors.New("unknown algorithm")
+	// ErrWeakChecksum strongest checksum of a version is below the minimum algorithm policy
+	ErrWeakChecksum = errors.New("weak checksum")
+	// ErrNetwork download failed
+	ErrNetwork = errors.New("network error")
+	// ErrOffline network access required in offline mode
+	ErrOffline = errors.New("offline")
+	// ErrConflict dependency requirements can not be satisfied together
+	ErrConflict = errors.New("dependency conflict")
+	// ErrAlreadyInstalled package is already in classpath
+	ErrAlreadyInstalled = errors.New("already installed")
+	// ErrNotInstalled package is not in classpath
+	ErrNotInstalled = errors.New("not installed")
+)
+
+// Error user facing message classified by one of the typed errors
+type Error struct {
+	Kind error
+	Msg  string
+	Err  error
+	Code int
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Unwrap match both the kind and the underlying cause
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// New error of kind with message
+func New(kind error, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+// Newf error of kind with formatted message, %w wraps the cause
+func Newf(kind error, format string, a ...any) error {
+	err := fmt.Errorf(format, a...)
+	return &Error{Kind: kind, Msg: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// WithCode set process exit code for error
+func WithCode(err error, code int) error {
+	var e *Error
+	if errors.As(err, &e) {
+		e.Code = code
+		return e
+	}
+	return &Error{Msg: err.Error(), Err: err, Code: code}
+}
+
+// Is reports whether any error in err's tree matches target
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's tree that matches target
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// Code process exit code for error
+func Code(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e *Error
+	if errors.As(err, &e) && e.Code != 0 {
+		return e.Code
+	}
+	return 1
+}
+
 //Exit graceful exit with message
 func Exit(message string, code int) {
 	fmt.Println(message)
 	os.Exit(code)
 }
-
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewf(t *testing.T) {
+	cause := fmt.Errorf("connection refused")
+	err := Newf(ErrNetwork, "unable to download from %s: %w", "http://localhost", cause)
+	if err.Error() != "unable to download from http://localhost: connection refused" {
+		t.Errorf("Error() = %v", err)
+	}
+	if !Is(err, ErrNetwork) || !Is(err, cause) {
+		t.Errorf("Expected %v to match both its kind and cause", err)
+	}
+	if Is(err, ErrOffline) {
+		t.Errorf("Expected %v not to match %v", err, ErrOffline)
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "plain", err: errors.New("failed"), want: 1},
+		{name: "typed", err: New(ErrNotInstalled, "x is not installed."), want: 1},
+		{name: "with code", err: WithCode(New(ErrNotInstalled, "x is not installed."), 2), want: 2},
+		{name: "wrapped with code", err: fmt.Errorf("outer: %w", WithCode(errors.New("failed"), 3)), want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
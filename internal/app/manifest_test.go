@@ -0,0 +1,51 @@
+package app
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	other, _, _ := ed25519.GenerateKey(nil)
+	path := filepath.Join(t.TempDir(), "packages.json")
+	manifest := []byte(`[{"name":"alpha","category":"driver","versions":[]}]`)
+	os.WriteFile(path, manifest, 0664)
+	if err := SignManifest(path, base64.StdEncoding.EncodeToString(priv.Seed())); err != nil {
+		t.Fatalf("SignManifest() error = %v", err)
+	}
+	sig, _ := os.ReadFile(path + SignatureSuffix)
+	keys, err := ParseManifestKeys("# trusted\n" + base64.StdEncoding.EncodeToString(other) + "," + base64.StdEncoding.EncodeToString(pub))
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("ParseManifestKeys() = %v, error = %v", keys, err)
+	}
+
+	tests := []struct {
+		name     string
+		manifest []byte
+		sig      []byte
+		keys     []ed25519.PublicKey
+		wantErr  bool
+	}{
+		{name: "Can Verify Signature", manifest: manifest, sig: sig, keys: keys},
+		{name: "Can Detect Tampered Manifest", manifest: []byte(`[]`), sig: sig, keys: keys, wantErr: true},
+		{name: "Can Detect Untrusted Key", manifest: manifest, sig: sig, keys: keys[:1], wantErr: true},
+		{name: "Can Detect Missing Keys", manifest: manifest, sig: sig, wantErr: true},
+		{name: "Can Detect Malformed Signature", manifest: manifest, sig: []byte("not a signature"), keys: keys, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyManifest(tt.manifest, tt.sig, tt.keys)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, errors.ErrBadSignature)) {
+				t.Errorf("VerifyManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err = ParseManifestKeys("abc"); err == nil {
+		t.Errorf("Expected ParseManifestKeys() to reject invalid key")
+	}
+}
@@ -0,0 +1,136 @@
+package packages
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"strings"
+)
+
+// Latest constraint keyword resolving to the newest compatible version
+const Latest = "latest"
+
+// Upgrade policies bounding how far a version may move from the installed one
+const (
+	Patch = "patch"
+	Minor = "minor"
+	Major = "major"
+)
+
+// ParseConstraint convert a liquibase.json version requirement to go-version constraints.
+// Supports exact tags, comparisons (">=4.20 <5" or ">=4.20, <5"), "~>" and the npm style "^" and "~" ranges.
+func ParseConstraint(s string) (version.Constraints, error) {
+	var parts []string
+	tokens := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		// Allow whitespace between operator and version, e.g. ">= 4.20"
+		if strings.Trim(t, "<>=!~") == "" && i+1 < len(tokens) {
+			i++
+			t = t + tokens[i]
+		}
+		switch {
+		case strings.HasPrefix(t, "^"):
+			r, err := caretRange(strings.TrimPrefix(t, "^"))
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, r)
+		case strings.HasPrefix(t, "~") && !strings.HasPrefix(t, "~>"):
+			r, err := tildeRange(strings.TrimPrefix(t, "~"))
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, r)
+		default:
+			parts = append(parts, t)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	return version.NewConstraint(strings.Join(parts, ", "))
+}
+
+// IsRange version requirement is a range or keyword rather than an exact tag
+func IsRange(s string) bool {
+	return s == Latest || strings.ContainsAny(s, "^~<>=!, ")
+}
+
+// MatchesConstraint version tag satisfies liquibase.json version requirement
+func MatchesConstraint(tag string, s string) bool {
+	if tag == s || s == Latest {
+		return true
+	}
+	if !IsRange(s) {
+		return false
+	}
+	c, err := ParseConstraint(s)
+	if err != nil {
+		return false
+	}
+	v, err := version.NewVersion(tag)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
+
+// PolicyConstraint version range an upgrade from installed tag may pick under policy, empty when unbounded
+func PolicyConstraint(installed string, policy string) (string, error) {
+	v, err := version.NewVersion(installed)
+	if err != nil {
+		return "", err
+	}
+	seg := v.Segments()
+	switch policy {
+	case Patch:
+		return fmt.Sprintf(">= %s, < %d.%d.0", installed, seg[0], seg[1]+1), nil
+	case Minor:
+		return fmt.Sprintf(">= %s, < %d.0.0", installed, seg[0]+1), nil
+	case Major, "":
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown upgrade policy %s", policy)
+}
+
+// caretRange allow changes that do not modify the left-most non-zero segment
+func caretRange(s string) (string, error) {
+	v, err := version.NewVersion(s)
+	if err != nil {
+		return "", err
+	}
+	seg := v.Segments()
+	n := precision(s)
+	var upper string
+	switch {
+	case seg[0] > 0 || n == 1:
+		upper = fmt.Sprintf("%d.0.0", seg[0]+1)
+	case seg[1] > 0 || n == 2:
+		upper = fmt.Sprintf("0.%d.0", seg[1]+1)
+	default:
+		upper = fmt.Sprintf("0.0.%d", seg[2]+1)
+	}
+	return ">= " + s + ", < " + upper, nil
+}
+
+// tildeRange allow patch level changes, or minor level changes when only a major version is given
+func tildeRange(s string) (string, error) {
+	v, err := version.NewVersion(s)
+	if err != nil {
+		return "", err
+	}
+	seg := v.Segments()
+	var upper string
+	if precision(s) == 1 {
+		upper = fmt.Sprintf("%d.0.0", seg[0]+1)
+	} else {
+		upper = fmt.Sprintf("%d.%d.0", seg[0], seg[1]+1)
+	}
+	return ">= " + s + ", < " + upper, nil
+}
+
+// precision number of version segments written in a partial version, e.g. "4.5" is 2
+func precision(s string) int {
+	core := strings.SplitN(strings.TrimPrefix(s, "v"), "-", 2)[0]
+	return len(strings.Split(core, "."))
+}
@@ -0,0 +1,64 @@
+package packages
+
+import (
+	"context"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"sync"
+)
+
+// DefaultJobs number of concurrent downloads
+const DefaultJobs = 4
+
+// InstallAll install versions into classpath using a bounded pool of workers.
+// The first failure cancels the remaining downloads and is returned.
+func InstallAll(ctx context.Context, o Options, cp string, vs []Version, jobs int, p *utils.Progress) error {
+	if !ClasspathExists(cp) {
+		createClasspath(cp)
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := make(chan Version)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var first error
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range queue {
+				var err error
+				if v.PathIsHTTP() {
+					err = v.Download(ctx, o, cp, p)
+				} else {
+					err = v.CopyToClassPath(cp)
+				}
+				if err != nil {
+					once.Do(func() {
+						first = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for _, v := range vs {
+		select {
+		case queue <- v:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if first == nil {
+		first = ctx.Err()
+	}
+	return first
+}
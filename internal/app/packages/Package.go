@@ -11,6 +11,8 @@ type Package struct {
 	Name     string    `json:"name"`
 	Category string    `json:"category"`
 	Versions []Version `json:"versions"`
+	// Registry manifest location the package was loaded from
+	Registry string `json:"-"`
 }
 
 // GetLatestVersion from Package
@@ -33,6 +35,18 @@ func (p Package) GetLatestVersion(lb *version.Version) Version {
 	return ver
 }
 
+// IsCompatible version can be used with liquibase version, drivers are always compatible
+func (p Package) IsCompatible(v Version, lb *version.Version) bool {
+	if p.Category == "driver" || lb == nil {
+		return true
+	}
+	core, err := version.NewVersion(v.LiquibaseCore)
+	if err != nil {
+		return true
+	}
+	return !lb.LessThan(core)
+}
+
 // GetVersion from package by version tag
 func (p Package) GetVersion(v string) Version {
 	var r Version
@@ -44,6 +58,39 @@ func (p Package) GetVersion(v string) Version {
 	return r
 }
 
+// ResolveVersion from package by exact tag, version range, or "latest", compatible with liquibase version
+func (p Package) ResolveVersion(c string, lb *version.Version) (Version, error) {
+	if c == "" || c == Latest {
+		return p.GetLatestVersion(lb), nil
+	}
+	if v := p.GetVersion(c); v.Tag != "" {
+		return v, nil
+	}
+	cs, err := ParseConstraint(c)
+	if err != nil {
+		return Version{}, err
+	}
+	var ver Version
+	old, _ := version.NewVersion("0.0.0")
+	for _, v := range p.Versions {
+		if p.Category != "driver" && lb != nil {
+			req, _ := version.NewVersion(v.LiquibaseCore)
+			if req != nil && lb.LessThan(req) {
+				continue
+			}
+		}
+		n, err := version.NewVersion(v.Tag)
+		if err != nil || !cs.Check(n) {
+			continue
+		}
+		if old.LessThan(n) {
+			old = n
+			ver = v
+		}
+	}
+	return ver, nil
+}
+
 // GetInstalledVersion from classpath files
 func (p Package) GetInstalledVersion(files []fs.FileInfo) Version {
 	var r Version
@@ -58,25 +58,58 @@ func (ps Packages) FilterByCategory(c string) Packages {
 	return r
 }
 
+// Merge packages of a lower priority registry, packages already present by name are kept
+func (ps Packages) Merge(lower Packages) Packages {
+	r := append(Packages{}, ps...)
+	for _, p := range lower {
+		if ps.GetByName(p.Name).Name == "" {
+			r = append(r, p)
+		}
+	}
+	return r
+}
+
+// WithRegistry set the registry packages were loaded from
+func (ps Packages) WithRegistry(source string) Packages {
+	r := make(Packages, len(ps))
+	for i, p := range ps {
+		p.Registry = source
+		r[i] = p
+	}
+	return r
+}
+
 // Display generate display table for packages
 func (ps Packages) Display(files []fs.FileInfo) []string {
 	var r []string
-	var prefix string
 	r = append(r, fmt.Sprintf("%-4s %-38s %s", "   ", "Package", "Category"))
 	for i, p := range ps {
-		if (i + 1) == len(ps) {
-			prefix = "└──"
-		} else {
-			prefix = "├──"
-		}
-		var v string
-		tag := p.GetInstalledVersion(files).Tag
-		if tag != "" {
-			v = "@" + tag
-		} else {
-			v = tag
-		}
-		r = append(r, fmt.Sprintf("%-4s %-38s %s", prefix, p.Name+v, p.Category))
+		r = append(r, fmt.Sprintf("%-4s %-38s %s", treePrefix(i, len(ps)), p.displayName(files), p.Category))
 	}
 	return r
 }
+
+// DisplayRegistries generate display table for packages with the registry each one was loaded from
+func (ps Packages) DisplayRegistries(files []fs.FileInfo) []string {
+	var r []string
+	r = append(r, fmt.Sprintf("%-4s %-38s %-12s %s", "   ", "Package", "Category", "Registry"))
+	for i, p := range ps {
+		r = append(r, fmt.Sprintf("%-4s %-38s %-12s %s", treePrefix(i, len(ps)), p.displayName(files), p.Category, p.Registry))
+	}
+	return r
+}
+
+// displayName package name with the installed version, if any
+func (p Package) displayName(files []fs.FileInfo) string {
+	if tag := p.GetInstalledVersion(files).Tag; tag != "" {
+		return p.Name + "@" + tag
+	}
+	return p.Name
+}
+
+func treePrefix(i int, n int) string {
+	if (i + 1) == n {
+		return "└──"
+	}
+	return "├──"
+}
@@ -0,0 +1,113 @@
+package packages
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"io/fs"
+	"sort"
+)
+
+// Requirement package name and version constraint requested for install
+type Requirement struct {
+	Name       string
+	Constraint string
+}
+
+// Resolution package version selected by the Resolver
+type Resolution struct {
+	Package    Package
+	Version    Version
+	RequiredBy string // empty for requested packages
+	Installed  bool   // version is already present in classpath
+}
+
+// Resolver resolves requirements and their transitive dependencies against the manifest
+type Resolver struct {
+	Packages  Packages
+	Liquibase *version.Version
+	Installed []fs.FileInfo      // classpath files, installed versions are kept when they satisfy a requirement
+	Locked    map[string]Version // preferred versions, e.g. from liquibase.lock.json
+}
+
+// Resolve dependency graph for requirements in install order, dependencies before dependents
+func (r Resolver) Resolve(reqs []Requirement) ([]Resolution, error) {
+	s := resolveState{Resolver: r, chosen: map[string]*Resolution{}, required: map[string]string{}}
+	for _, req := range reqs {
+		if err := s.visit(req, ""); err != nil {
+			return nil, err
+		}
+	}
+	return s.order, nil
+}
+
+type resolveState struct {
+	Resolver
+	chosen   map[string]*Resolution
+	required map[string]string // constraint that selected each package, for conflict messages
+	order    []Resolution
+}
+
+func (s *resolveState) visit(req Requirement, from string) error {
+	if c, ok := s.chosen[req.Name]; ok {
+		if !MatchesConstraint(c.Version.Tag, req.Constraint) && req.Constraint != "" {
+			return errors.Newf(errors.ErrConflict, "dependency conflict: %s requires %s@%s but %s@%s was selected for %s",
+				describe(from), req.Name, req.Constraint, req.Name, c.Version.Tag, describe(c.RequiredBy))
+		}
+		return nil
+	}
+	p := s.Packages.GetByName(req.Name)
+	if _, ok := s.Locked[req.Name]; ok && p.Name == "" {
+		// Locked packages remain installable after they are dropped from the manifest
+		p = Package{Name: req.Name}
+	}
+	if p.Name == "" {
+		return errors.Newf(errors.ErrPackageNotFound, "package '%s' required by %s not found", req.Name, describe(from))
+	}
+
+	res := &Resolution{Package: p, RequiredBy: from}
+	if v := p.GetInstalledVersion(s.Installed); v.Tag != "" {
+		if req.Constraint != "" && !MatchesConstraint(v.Tag, req.Constraint) {
+			return errors.Newf(errors.ErrConflict, "dependency conflict: %s requires %s@%s but %s@%s is installed",
+				describe(from), req.Name, req.Constraint, req.Name, v.Tag)
+		}
+		res.Version = v
+		res.Installed = true
+	} else if v, ok := s.Locked[req.Name]; ok && (req.Constraint == "" || MatchesConstraint(v.Tag, req.Constraint)) {
+		res.Version = v
+	} else {
+		v, err := p.ResolveVersion(req.Constraint, s.Liquibase)
+		if err != nil {
+			return fmt.Errorf("invalid version '%s' for %s: %s", req.Constraint, req.Name, err.Error())
+		}
+		if v.Tag == "" {
+			return errors.Newf(errors.ErrPackageNotFound, "unable to find a version of %s matching '%s' required by %s", req.Name, req.Constraint, describe(from))
+		}
+		res.Version = v
+	}
+	if !res.Installed && !p.IsCompatible(res.Version, s.Liquibase) {
+		return errors.Newf(errors.ErrIncompatible, "%s@%s is not compatible with liquibase v%s", p.Name, res.Version.Tag, s.Liquibase.String())
+	}
+	s.chosen[req.Name] = res
+
+	// Visit dependencies in a stable order so installs are reproducible
+	var names []string
+	for n := range res.Version.Dependencies {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := s.visit(Requirement{Name: n, Constraint: res.Version.Dependencies[n]}, p.Name); err != nil {
+			return err
+		}
+	}
+	s.order = append(s.order, *res)
+	return nil
+}
+
+func describe(from string) string {
+	if from == "" {
+		return "request"
+	}
+	return from
+}
@@ -0,0 +1,127 @@
+package packages
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"os"
+	"strings"
+)
+
+// LoadKeyring read armored or binary OpenPGP public keys from file
+func LoadKeyring(path string) (openpgp.EntityList, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keyring %s: %w", path, err)
+	}
+	kr, err := ReadKeyring(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keyring %s: %w", path, err)
+	}
+	return kr, nil
+}
+
+// ReadKeyring armored or binary OpenPGP public keys, empty contents have no keys
+func ReadKeyring(b []byte) (openpgp.EntityList, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+	if isArmored(b) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(b))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(b))
+}
+
+// NormalizeFingerprint upper case hex fingerprint without spaces or 0x prefix
+func NormalizeFingerprint(f string) string {
+	f = strings.ToUpper(strings.ReplaceAll(f, " ", ""))
+	return strings.TrimPrefix(f, "0X")
+}
+
+// VerifySignature check the detached signature of version installed in cp against the Keyring of o,
+// and that it was made by the key with Fingerprint when set. Unsigned versions, and every version when
+// the keyring is empty, fail with ErrUnsigned. Verified remote signatures are kept in the download cache.
+func (v Version) VerifySignature(ctx context.Context, o Options, cp string) error {
+	if v.Signature == "" {
+		return errors.Newf(errors.ErrUnsigned, "%s is not signed", v.GetFilename())
+	}
+	if len(o.Keyring) == 0 {
+		return errors.Newf(errors.ErrUnsigned, "no trusted keys to check the signature of %s", v.GetFilename())
+	}
+	e, cached := v.cacheEntry(o)
+	sig, err := e.Signature()
+	if !cached || err != nil {
+		if sig, err = v.readSignature(ctx, o.HTTP); err != nil {
+			return err
+		}
+	}
+	f, err := os.Open(cp + v.GetFilename())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var signer *openpgp.Entity
+	if isArmored(sig) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(o.Keyring, f, bytes.NewReader(sig), nil)
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(o.Keyring, f, bytes.NewReader(sig), nil)
+	}
+	if err != nil {
+		return errors.Newf(errors.ErrBadSignature, "signature verification failed for %s: %w", v.GetFilename(), err)
+	}
+	if v.Fingerprint != "" && !HasFingerprint(signer, NormalizeFingerprint(v.Fingerprint)) {
+		return errors.Newf(errors.ErrBadSignature, "%s is signed by %X, expected %s",
+			v.GetFilename(), signer.PrimaryKey.Fingerprint, NormalizeFingerprint(v.Fingerprint))
+	}
+	if cached {
+		// Offline installs verify with the stored signature, a failure here only costs a future download
+		e.PutSignature(sig)
+	}
+	return nil
+}
+
+// cacheEntry download cache entry of remote version
+func (v Version) cacheEntry(o Options) (cache.Entry, bool) {
+	if !v.PathIsHTTP() {
+		return cache.Entry{}, false
+	}
+	alg, sum, err := v.digest(o.MinAlgorithm)
+	if err != nil {
+		return cache.Entry{}, false
+	}
+	return o.Cache.Lookup(alg, sum)
+}
+
+// readSignature detached signature contents from URL or local path
+func (v Version) readSignature(ctx context.Context, h utils.HTTPUtil) ([]byte, error) {
+	if strings.HasPrefix(v.Signature, "http") {
+		return h.Read(ctx, v.Signature)
+	}
+	b, err := os.ReadFile(strings.TrimPrefix(v.Signature, "file://"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read signature %s", v.Signature)
+	}
+	return b, nil
+}
+
+// HasFingerprint primary key or a subkey of entity has the normalized fingerprint
+func HasFingerprint(signer *openpgp.Entity, fingerprint string) bool {
+	if fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint) == fingerprint {
+		return true
+	}
+	for _, k := range signer.Subkeys {
+		if fmt.Sprintf("%X", k.PublicKey.Fingerprint) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+func isArmored(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("-----BEGIN"))
+}
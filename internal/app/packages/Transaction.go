@@ -0,0 +1,170 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"os"
+)
+
+// Transaction stages classpath changes and applies them all or none.
+// New versions are downloaded and verified in a staging directory inside the classpath,
+// replaced and removed jars are moved to a backup directory until the changes are committed.
+type Transaction struct {
+	cp      string
+	opts    Options
+	install []Version
+	remove  []Version
+	tracked map[string][]byte // project files restored on rollback, nil contents when the file did not exist
+
+	staging    string
+	backup     string
+	committed  []string  // filenames moved into classpath
+	moved      []string  // filenames moved out of classpath into backup
+	unverified []Version // installed versions whose signature could not be checked
+}
+
+// Begin transaction on classpath, downloading and verifying with o
+func Begin(cp string, o Options) *Transaction {
+	return &Transaction{cp: cp, opts: o, tracked: map[string][]byte{}}
+}
+
+// Install add versions to install on commit
+func (t *Transaction) Install(vs ...Version) {
+	t.install = append(t.install, vs...)
+}
+
+// Remove add installed versions to remove on commit
+func (t *Transaction) Remove(vs ...Version) {
+	t.remove = append(t.remove, vs...)
+}
+
+// Track snapshot files, such as liquibase.json, restored when the transaction fails
+func (t *Transaction) Track(paths ...string) error {
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			t.tracked[p] = nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		t.tracked[p] = b
+	}
+	return nil
+}
+
+// Apply download and verify every version, then move them into the classpath, remove old versions and run write.
+// Any failure restores the previous jars and tracked files and is returned.
+func (t *Transaction) Apply(ctx context.Context, jobs int, p *utils.Progress, write func() error) (err error) {
+	if !ClasspathExists(t.cp) {
+		if err = createClasspath(t.cp); err != nil {
+			return fmt.Errorf("unable to create classpath located at %s", t.cp)
+		}
+	}
+	if t.staging, err = os.MkdirTemp(t.cp, ".lpm-staging-"); err != nil {
+		return fmt.Errorf("unable to access classpath located at %s", t.cp)
+	}
+	t.staging += string(os.PathSeparator)
+	defer os.RemoveAll(t.staging)
+	if t.backup, err = os.MkdirTemp(t.cp, ".lpm-backup-"); err != nil {
+		return fmt.Errorf("unable to access classpath located at %s", t.cp)
+	}
+	t.backup += string(os.PathSeparator)
+	defer func() {
+		if err != nil {
+			if rerr := t.rollback(); rerr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v, previous files are kept in %s)", err, rerr, t.backup)
+				return
+			}
+		}
+		os.RemoveAll(t.backup)
+	}()
+
+	// Stage and verify everything before touching the classpath
+	if err = InstallAll(ctx, t.opts, t.staging, t.install, jobs, p); err != nil {
+		return err
+	}
+	for _, v := range t.install {
+		if _, sum := v.Digest(); sum != "" {
+			if err = v.verifyChecksum(t.staging, t.opts.MinAlgorithm); err != nil {
+				return err
+			}
+		}
+		err = v.VerifySignature(ctx, t.opts, t.staging)
+		if errors.Is(err, errors.ErrUnsigned) && !t.opts.RequireSignatures {
+			t.unverified = append(t.unverified, v)
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commit
+	for _, v := range t.install {
+		name := v.GetFilename()
+		if err = t.moveOut(name); err != nil {
+			return err
+		}
+		if err = os.Rename(t.staging+name, t.cp+name); err != nil {
+			return fmt.Errorf("unable to install %s in classpath: %w", name, err)
+		}
+		t.committed = append(t.committed, name)
+	}
+	for _, v := range t.remove {
+		if err = t.moveOut(v.GetFilename()); err != nil {
+			return fmt.Errorf("Unable to remove %s from classpath.", v.GetFilename())
+		}
+	}
+	if write != nil {
+		err = write()
+	}
+	return err
+}
+
+// Unverified versions installed by Apply without a signature check
+func (t *Transaction) Unverified() []Version {
+	return t.unverified
+}
+
+// moveOut move existing classpath file to backup
+func (t *Transaction) moveOut(name string) error {
+	if _, err := os.Stat(t.cp + name); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.Rename(t.cp+name, t.backup+name); err != nil {
+		return err
+	}
+	t.moved = append(t.moved, name)
+	return nil
+}
+
+// rollback undo committed changes in reverse order and restore tracked files
+func (t *Transaction) rollback() error {
+	var first error
+	keep := func(err error) {
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	for i := len(t.committed) - 1; i >= 0; i-- {
+		keep(os.Remove(t.cp + t.committed[i]))
+	}
+	for i := len(t.moved) - 1; i >= 0; i-- {
+		keep(os.Rename(t.backup+t.moved[i], t.cp+t.moved[i]))
+	}
+	for p, b := range t.tracked {
+		if b == nil {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				keep(err)
+			}
+			continue
+		}
+		keep(os.WriteFile(p, b, 0664))
+	}
+	t.committed, t.moved = nil, nil
+	return first
+}
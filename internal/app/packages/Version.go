@@ -1,19 +1,30 @@
 package packages
 
 import (
-	"bytes"
-	"crypto/sha1"
-	"crypto/sha256"
+	"context"
 	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io"
 	"io/fs"
 	"os"
-	"package-manager/internal/app/errors"
-	"package-manager/internal/app/utils"
 	"path/filepath"
 	"strings"
 )
 
+// Options how versions are downloaded and verified.
+// The zero value uses the default HTTP settings and download cache, accepts SHA1 and does not check signatures.
+type Options struct {
+	HTTP         utils.HTTPUtil
+	Cache        cache.Cache
+	MinAlgorithm string             // weakest checksum algorithm accepted, SHA1 when empty
+	Keyring      openpgp.EntityList // trusted public keys for artifact signatures
+	// RequireSignatures reject versions whose signature can not be checked instead of reporting them as unverified
+	RequireSignatures bool
+}
+
 // Version struct
 type Version struct {
 	Tag           string `json:"tag"`
@@ -21,6 +32,14 @@ type Version struct {
 	Algorithm     string `json:"algorithm"`
 	CheckSum      string `json:"checksum"`
 	LiquibaseCore string `json:"liquibaseCore"`
+	// Checksums digests by algorithm, e.g. "SHA512", in addition to Algorithm and CheckSum
+	Checksums map[string]string `json:"checksums,omitempty"`
+	// Signature URL or local path of the detached OpenPGP signature, e.g. the .asc published to Maven Central
+	Signature string `json:"signature,omitempty"`
+	// Fingerprint of the key expected to have made Signature
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Dependencies other packages required by this version, as "name": "version constraint"
+	Dependencies map[string]string `json:"dependencies,omitempty"`
 }
 
 // GetFilename from version
@@ -45,66 +64,161 @@ func (v Version) PathIsHTTP() bool {
 	return strings.HasPrefix(v.Path, "http")
 }
 
+// LocalPath file system path of local version, with any file:// scheme removed
+func (v Version) LocalPath() string {
+	return strings.TrimPrefix(v.Path, "file://")
+}
+
+// AvailableLocally version can be installed without network access, from a local path or the download cache.
+// Cached versions whose checksum Download would reject, or whose remote signature is not cached, are not available.
+func (v Version) AvailableLocally(o Options) bool {
+	if !v.PathIsHTTP() {
+		_, err := os.Stat(v.LocalPath())
+		return err == nil
+	}
+	alg, sum, err := v.digest(o.MinAlgorithm)
+	if err != nil {
+		return false
+	}
+	e, ok := o.Cache.Lookup(alg, sum)
+	if ok && len(o.Keyring) > 0 && strings.HasPrefix(v.Signature, "http") {
+		_, err = e.Signature()
+		return err == nil
+	}
+	return ok
+}
+
+// Digest strongest checksum algorithm and digest of version, from Checksums or Algorithm and CheckSum
+func (v Version) Digest() (string, string) {
+	alg, sum := strings.ToUpper(v.Algorithm), v.CheckSum
+	for a, s := range v.Checksums {
+		if utils.Strength(a) > utils.Strength(alg) {
+			alg, sum = strings.ToUpper(a), s
+		}
+	}
+	return alg, sum
+}
+
+// digest strongest checksum of version in lower case, failing when it is unknown, malformed or weaker than min
+func (v Version) digest(min string) (string, string, error) {
+	alg, sum := v.Digest()
+	sum = strings.ToLower(sum)
+	if utils.Strength(alg) == 0 {
+		return alg, sum, errors.Newf(errors.ErrUnknownAlgorithm, "unknown algorithm %s for %s", alg, v.GetFilename())
+	}
+	if !utils.ValidChecksum(alg, sum) {
+		return alg, sum, errors.Newf(errors.ErrChecksumMismatch, "invalid %s checksum %q for %s in the manifest", alg, sum, v.GetFilename())
+	}
+	if min == "" {
+		min = "SHA1"
+	}
+	if utils.Strength(alg) < utils.Strength(min) {
+		return alg, sum, errors.Newf(errors.ErrWeakChecksum, "%s only has a %s checksum, %s or stronger is required",
+			v.GetFilename(), alg, strings.ToUpper(min))
+	}
+	return alg, sum, nil
+}
+
 // CopyToClassPath install local version to classpath
-func (v Version) CopyToClassPath(cp string) {
+func (v Version) CopyToClassPath(cp string) error {
 	if !ClasspathExists(cp) {
 		createClasspath(cp)
 	}
-	source, err := os.Open(v.Path)
+	source, err := os.Open(v.LocalPath())
 	if err != nil {
-		errors.Exit("Unable to open "+v.Path, 1)
+		return fmt.Errorf("unable to open %s", v.Path)
 	}
 	defer source.Close()
-	b, err := io.ReadAll(source)
-	if err != nil {
-		errors.Exit(err.Error(), 1)
-	}
-	writeToDestination(cp+v.GetFilename(), b, v.GetFilename())
+	return utils.WriteAtomic(cp+v.GetFilename(), source, nil)
 }
 
-func writeToDestination(d string, b []byte, f string) {
-	destination, err := os.Create(d)
+// Download install remote version to classpath from the download cache or URL, reporting progress
+func (v Version) Download(ctx context.Context, o Options, cp string, p *utils.Progress) error {
+	if !ClasspathExists(cp) {
+		createClasspath(cp)
+	}
+	alg, sum, err := v.digest(o.MinAlgorithm)
 	if err != nil {
-		errors.Exit("Unable to access classpath located at "+d, 1)
+		return err
 	}
-	defer destination.Close()
-	_, err = io.Copy(destination, bytes.NewReader(b))
+	if e, ok := o.Cache.Lookup(alg, sum); ok {
+		if err := e.Verify(); err == nil {
+			if err = e.CopyTo(cp + v.GetFilename()); err != nil {
+				return fmt.Errorf("unable to install %s in classpath", v.GetFilename())
+			}
+			bar := p.Add(v.GetFilename(), e.Size)
+			bar.Reset(e.Size)
+			bar.Done("cached")
+			return nil
+		}
+		// Corrupt cache entry, discard it and download again
+		e.Remove()
+	}
+	h, err := utils.NewHash(alg)
+	if err != nil {
+		return errors.Newf(errors.ErrUnknownAlgorithm, "unknown algorithm %s", alg)
+	}
+	body, size, err := o.HTTP.Fetch(ctx, v.Path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Stream to a temp file in the classpath, hashing while writing, and only rename it into place once verified
+	bar := p.Add(v.GetFilename(), size)
+	err = utils.WriteAtomic(cp+v.GetFilename(), io.TeeReader(body, io.MultiWriter(h, bar)), func() error {
+		if !strings.EqualFold(fmt.Sprintf("%x", h.Sum(nil)), sum) {
+			return errors.Newf(errors.ErrChecksumMismatch, "checksum validation failed for %s, aborting download", v.GetFilename())
+		}
+		return nil
+	})
 	if err != nil {
-		errors.Exit("Unable to install "+f+" in classpath.", 1)
+		bar.Done("failed")
+		return err
 	}
+	bar.Done("verified")
+
+	// Share verified download with other projects, a failure here only costs a future download
+	if f, err := os.Open(cp + v.GetFilename()); err == nil {
+		o.Cache.Put(alg, sum, v.GetFilename(), f)
+		f.Close()
+	}
+	return nil
 }
 
-func (v Version) calcChecksum(b []byte) string {
-	var r string
-	switch v.Algorithm {
-	case "SHA1":
-		r = fmt.Sprintf("%x", sha1.Sum(b))
-	case "SHA256":
-		r = fmt.Sprintf("%x", sha256.Sum256(b))
-	default:
-		errors.Exit("Unknown Algorithm.", 1)
+// Verify re-hash version installed in classpath against its checksum and check jar integrity
+func (v Version) Verify(cp string, o Options) error {
+	if err := v.verifyChecksum(cp, o.MinAlgorithm); err != nil {
+		return err
 	}
-	return r
+	path := cp + v.GetFilename()
+	if strings.HasSuffix(path, ".jar") {
+		if err := utils.CheckZip(path); err != nil {
+			return errors.Newf(errors.ErrCorrupt, "%s is corrupt: %w", v.GetFilename(), err)
+		}
+	}
+	return nil
 }
 
-// DownloadToClassPath install remote version to classpath
-func (v Version) DownloadToClassPath(cp string) {
-	if !ClasspathExists(cp) {
-		createClasspath(cp)
+// verifyChecksum re-hash version installed in classpath with its strongest checksum
+func (v Version) verifyChecksum(cp string, min string) error {
+	alg, want, err := v.digest(min)
+	if err != nil {
+		return err
+	}
+	sum, err := utils.FileChecksum(alg, cp+v.GetFilename())
+	if err != nil {
+		return err
 	}
-	body := utils.HTTPUtil{}.Get(v.Path)
-	sha := v.calcChecksum(body)
-	if sha == v.CheckSum {
-		fmt.Println("Checksum verified. Installing " + v.GetFilename() + " to " + cp)
-	} else {
-		errors.Exit("Checksum validation failed. Aborting download.", 1)
+	if !strings.EqualFold(sum, want) {
+		return errors.Newf(errors.ErrChecksumMismatch, "checksum mismatch for %s: expected %s %s but got %s", v.GetFilename(), alg, want, sum)
 	}
-	writeToDestination(cp+v.GetFilename(), body, v.GetFilename())
+	return nil
 }
 
 // createClasspath creates a proper directory at the specified location
 func createClasspath(cp string) error {
-	return os.Mkdir(cp, 0775)
+	return os.MkdirAll(cp, 0775)
 }
 
 // ClasspathExists checks to see if classpath directory is created
@@ -0,0 +1,117 @@
+package packages
+
+import (
+	"testing"
+)
+
+func TestMatchesConstraint(t *testing.T) {
+	type args struct {
+		tag string
+		c   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "Exact Tag", args: args{"4.5.0", "4.5.0"}, want: true},
+		{name: "Exact Tag Mismatch", args: args{"4.5.1", "4.5.0"}, want: false},
+		{name: "Latest", args: args{"4.5.1", "latest"}, want: true},
+		{name: "Caret Allows Minor", args: args{"4.9.2", "^4.5"}, want: true},
+		{name: "Caret Rejects Major", args: args{"5.0.0", "^4.5"}, want: false},
+		{name: "Caret Rejects Lower", args: args{"4.4.9", "^4.5"}, want: false},
+		{name: "Caret Zero Major", args: args{"0.3.0", "^0.2.1"}, want: false},
+		{name: "Tilde Allows Patch", args: args{"42.2.27", "~42.2"}, want: true},
+		{name: "Tilde Rejects Minor", args: args{"42.3.0", "~42.2"}, want: false},
+		{name: "Tilde Major Only", args: args{"42.9.0", "~42"}, want: true},
+		{name: "Pessimistic", args: args{"4.6.0", "~> 4.5"}, want: true},
+		{name: "Space Separated Range", args: args{"4.24.0", ">=4.20 <5"}, want: true},
+		{name: "Space Separated Range Upper", args: args{"5.0.1", ">=4.20 <5"}, want: false},
+		{name: "Comma Separated Range", args: args{"4.20.0", ">= 4.20, < 5"}, want: true},
+		{name: "Prefixed Tag", args: args{"v1.0.3", "^1.0"}, want: true},
+		{name: "Invalid Constraint", args: args{"1.0.0", "^abc"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesConstraint(tt.args.tag, tt.args.c); got != tt.want {
+				t.Errorf("MatchesConstraint(%v, %v) = %v, want %v", tt.args.tag, tt.args.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRange(t *testing.T) {
+	tests := []struct {
+		name string
+		c    string
+		want bool
+	}{
+		{name: "Exact Tag", c: "4.5.0", want: false},
+		{name: "Prefixed Tag", c: "v1.0.0", want: false},
+		{name: "Latest", c: "latest", want: true},
+		{name: "Caret", c: "^4.5", want: true},
+		{name: "Comparison", c: ">=4.20 <5", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRange(tt.c); got != tt.want {
+				t.Errorf("IsRange(%v) = %v, want %v", tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       string
+		wantErr bool
+	}{
+		{name: "Caret", c: "^4.5", wantErr: false},
+		{name: "Range", c: ">=4.20 <5", wantErr: false},
+		{name: "Empty", c: "", wantErr: true},
+		{name: "Garbage", c: "not-a-version", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConstraint(tt.c); (err != nil) != tt.wantErr {
+				t.Errorf("ParseConstraint(%v) error = %v, wantErr %v", tt.c, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		tag     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Patch Allows Patch", policy: Patch, tag: "4.20.3", want: true},
+		{name: "Patch Rejects Minor", policy: Patch, tag: "4.21.0", want: false},
+		{name: "Minor Allows Minor", policy: Minor, tag: "4.29.1", want: true},
+		{name: "Minor Rejects Major", policy: Minor, tag: "5.0.0", want: false},
+		{name: "Minor Rejects Lower", policy: Minor, tag: "4.19.0", want: false},
+		{name: "Major Unbounded", policy: Major, tag: "5.0.0", want: true},
+		{name: "Unknown Policy", policy: "huge", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := PolicyConstraint("4.20.1", tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PolicyConstraint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if c == "" {
+				c = Latest
+			}
+			if got := MatchesConstraint(tt.tag, c); got != tt.want {
+				t.Errorf("MatchesConstraint(%v, %v) = %v, want %v", tt.tag, c, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package packages
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestInstallAll(t *testing.T) {
+	local := func(v Version) Version {
+		v.Path = testPath + "/" + v.Path
+		return v
+	}
+	missing := Version{Tag: "9.9.9", Path: testPath + "/tests/mocks/files/missing-9.9.9.txt"}
+
+	tests := []struct {
+		name    string
+		vs      []Version
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Can Install Concurrently",
+			vs:   []Version{local(driverV1), local(driverV2), local(extensionV1), local(proV1)},
+			want: []string{"driver-0.0.1.txt", "driver-0.2.0.txt", "extension-0.0.2.txt", "pro-0.0.1.txt"},
+		},
+		{
+			name:    "Can Fail On Missing Artifact",
+			vs:      []Version{local(driverV1), missing},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := t.TempDir() + "/"
+			err := InstallAll(context.Background(), Options{}, cp, tt.vs, 2, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InstallAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, f := range tt.want {
+				if _, err := os.Stat(cp + f); err != nil {
+					t.Errorf("Expected %s in classpath", f)
+				}
+			}
+		})
+	}
+}
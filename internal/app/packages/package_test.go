@@ -2,28 +2,28 @@ package packages
 
 import (
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io/fs"
 	"os/exec"
-	"package-manager/internal/app/utils"
 	"reflect"
 	"strings"
 	"testing"
 )
 
 var driver = Package{
-	"driver",
-	"driver",
-	[]Version{driverV1, driverV2},
+	Name:     "driver",
+	Category: "driver",
+	Versions: []Version{driverV1, driverV2},
 }
 var extension = Package{
-	"extension",
-	"extension",
-	[]Version{extensionV1, extensionV2},
+	Name:     "extension",
+	Category: "extension",
+	Versions: []Version{extensionV1, extensionV2},
 }
 var pro = Package{
-	"pro",
-	"pro",
-	[]Version{proV1, proV2},
+	Name:     "pro",
+	Category: "pro",
+	Versions: []Version{proV1, proV2},
 }
 
 func TestPackage_GetLatestVersion(t *testing.T) {
@@ -243,3 +243,76 @@ func TestPackage_DeleteVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestPackage_ResolveVersion(t *testing.T) {
+	type args struct {
+		c  string
+		lb *version.Version
+	}
+
+	lbOld, _ := version.NewVersion("4.8.0")
+	lbNew, _ := version.NewVersion("4.17.2")
+
+	tests := []struct {
+		name    string
+		p       Package
+		args    args
+		want    Version
+		wantErr bool
+	}{
+		{
+			name: "Can Resolve Exact Tag",
+			p:    extension,
+			args: args{"0.0.2", lbNew},
+			want: extensionV1,
+		},
+		{
+			name: "Can Resolve Latest",
+			p:    extension,
+			args: args{"latest", lbNew},
+			want: extensionV2,
+		},
+		{
+			name: "Can Resolve Range: LB New",
+			p:    extension,
+			args: args{">=0.0.1 <2", lbNew},
+			want: extensionV2,
+		},
+		{
+			name: "Can Resolve Range: LB Old",
+			p:    extension,
+			args: args{">=0.0.1 <2", lbOld},
+			want: extensionV1,
+		},
+		{
+			name: "Can Resolve Driver Range Ignoring LB",
+			p:    driver,
+			args: args{"^0.2", lbOld},
+			want: driverV2,
+		},
+		{
+			name: "Can Not Resolve Unmatched Range",
+			p:    driver,
+			args: args{"^1.0", lbNew},
+			want: Version{},
+		},
+		{
+			name:    "Can Not Resolve Invalid Range",
+			p:       driver,
+			args:    args{"^abc", lbNew},
+			want:    Version{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.ResolveVersion(tt.args.c, tt.args.lb)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
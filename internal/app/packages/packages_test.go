@@ -2,9 +2,9 @@ package packages
 
 import (
 	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io/fs"
 	"os/exec"
-	"package-manager/internal/app/utils"
 	"reflect"
 	"strings"
 	"testing"
@@ -209,3 +209,39 @@ func TestPackages_GetOutdated(t *testing.T) {
 		})
 	}
 }
+
+func TestPackages_Merge(t *testing.T) {
+	local := Packages{Package{Name: "driver", Category: "driver", Registry: "local"}}
+	tests := []struct {
+		name  string
+		ps    Packages
+		lower Packages
+		want  Packages
+	}{
+		{
+			name:  "Can Merge Lower Priority Packages",
+			ps:    local,
+			lower: Packages{extension}.WithRegistry("remote"),
+			want:  Packages{local[0], Package{Name: "extension", Category: "extension", Versions: extension.Versions, Registry: "remote"}},
+		},
+		{
+			name:  "Keeps Higher Priority Package",
+			ps:    local,
+			lower: Packages{driver}.WithRegistry("remote"),
+			want:  local,
+		},
+		{
+			name:  "Can Merge Into Empty",
+			ps:    nil,
+			lower: local,
+			want:  local,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ps.Merge(tt.lower); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package packages
+
+import (
+	"github.com/hashicorp/go-version"
+	"reflect"
+	"testing"
+)
+
+var pluginV1 = Version{
+	Tag:           "1.0.0",
+	Path:          "tests/mocks/files/plugin-1.0.0.txt",
+	Algorithm:     "SHA1",
+	LiquibaseCore: "4.6.2",
+	Dependencies:  map[string]string{"driver": "^0.2", "helper": "latest"},
+}
+var helperV1 = Version{
+	Tag:           "1.0.0",
+	Path:          "tests/mocks/files/helper-1.0.0.txt",
+	Algorithm:     "SHA1",
+	LiquibaseCore: "4.6.2",
+	Dependencies:  map[string]string{"driver": ">=0.0.1"},
+}
+var plugin = Package{Name: "plugin", Category: "extension", Versions: []Version{pluginV1}}
+var helper = Package{Name: "helper", Category: "extension", Versions: []Version{helperV1}}
+
+func TestResolver_Resolve(t *testing.T) {
+	lbNew, _ := version.NewVersion("4.17.2")
+	graph := Packages{driver, extension, plugin, helper}
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		reqs     []Requirement
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "Can Resolve Dependencies Before Dependents",
+			resolver: Resolver{Packages: graph, Liquibase: lbNew},
+			reqs:     []Requirement{{Name: "plugin"}},
+			want:     []string{"driver@0.2.0", "helper@1.0.0", "plugin@1.0.0"},
+		},
+		{
+			name:     "Can Share Dependency With Requested Package",
+			resolver: Resolver{Packages: graph, Liquibase: lbNew},
+			reqs:     []Requirement{{Name: "driver", Constraint: "0.2.0"}, {Name: "plugin"}},
+			want:     []string{"driver@0.2.0", "helper@1.0.0", "plugin@1.0.0"},
+		},
+		{
+			name:     "Can Prefer Locked Version",
+			resolver: Resolver{Packages: graph, Liquibase: lbNew, Locked: map[string]Version{"driver": driverV1}},
+			reqs:     []Requirement{{Name: "helper"}},
+			want:     []string{"driver@0.0.1", "helper@1.0.0"},
+		},
+		{
+			name:     "Can Detect Conflicts",
+			resolver: Resolver{Packages: graph, Liquibase: lbNew},
+			reqs:     []Requirement{{Name: "driver", Constraint: "0.0.1"}, {Name: "plugin"}},
+			wantErr:  true,
+		},
+		{
+			name:     "Can Detect Conflicts With Installed Versions",
+			resolver: Resolver{Packages: graph, Liquibase: lbNew, Installed: installedFiles},
+			reqs:     []Requirement{{Name: "plugin"}},
+			wantErr:  true,
+		},
+		{
+			name:     "Can Detect Missing Dependencies",
+			resolver: Resolver{Packages: Packages{plugin}, Liquibase: lbNew},
+			reqs:     []Requirement{{Name: "plugin"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolver.Resolve(tt.reqs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, r := range got {
+				names = append(names, r.Package.Name+"@"+r.Version.Tag)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package packages
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newSigner(t *testing.T) *openpgp.Entity {
+	t.Helper()
+	e, err := openpgp.NewEntity("lpm test", "", "test@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestVersion_VerifySignature(t *testing.T) {
+	trusted, untrusted := newSigner(t), newSigner(t)
+	cp := t.TempDir() + "/"
+	os.WriteFile(cp+"driver-0.2.0.jar", []byte("Driver"), 0664)
+	sign := func(name string, e *openpgp.Entity, content string) string {
+		var b bytes.Buffer
+		if err := openpgp.ArmoredDetachSign(&b, e, bytes.NewReader([]byte(content)), nil); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(cp+name, b.Bytes(), 0664)
+		return cp + name
+	}
+	good := sign("good.asc", trusted, "Driver")
+	tampered := sign("tampered.asc", trusted, "Tampered")
+	foreign := sign("foreign.asc", untrusted, "Driver")
+	fingerprint := fmt.Sprintf("%X", trusted.PrimaryKey.Fingerprint)
+
+	o := Options{Keyring: openpgp.EntityList{trusted}}
+	tests := []struct {
+		name    string
+		version Version
+		want    error
+	}{
+		{name: "Can Verify Signature", version: Version{Path: "driver-0.2.0.jar", Signature: good, Fingerprint: fingerprint}},
+		{name: "Can Detect Unsigned Version", version: Version{Path: "driver-0.2.0.jar"}, want: errors.ErrUnsigned},
+		{name: "Can Detect Tampered File", version: Version{Path: "driver-0.2.0.jar", Signature: tampered}, want: errors.ErrBadSignature},
+		{name: "Can Detect Untrusted Key", version: Version{Path: "driver-0.2.0.jar", Signature: foreign}, want: errors.ErrBadSignature},
+		{name: "Can Detect Fingerprint Mismatch", version: Version{Path: "driver-0.2.0.jar", Signature: good, Fingerprint: "0000"}, want: errors.ErrBadSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.version.VerifySignature(context.Background(), o, cp)
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if err := (Version{Path: "driver-0.2.0.jar", Signature: good}).VerifySignature(context.Background(), Options{}, cp); !errors.Is(err, errors.ErrUnsigned) {
+		t.Errorf("VerifySignature() without keyring error = %v, want %v", err, errors.ErrUnsigned)
+	}
+}
+
+func TestVersion_VerifySignatureOffline(t *testing.T) {
+	signer := newSigner(t)
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, signer, strings.NewReader("DriverSHA1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".asc") {
+			w.Write(sig.Bytes())
+			return
+		}
+		w.Write([]byte("DriverSHA1"))
+	}))
+	defer srv.Close()
+	o := Options{Cache: cache.Cache{Dir: t.TempDir()}, Keyring: openpgp.EntityList{signer}}
+	v := Version{Path: srv.URL + "/driver-0.2.0.jar", Algorithm: "SHA1", CheckSum: "70daefe06dd19c073920273e02cfc712951795ea", Signature: srv.URL + "/driver-0.2.0.jar.asc"}
+
+	cp := t.TempDir() + "/"
+	tx := Begin(cp, o)
+	tx.Install(v)
+	if err := tx.Apply(context.Background(), 1, nil, nil); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	srv.Close()
+
+	o.HTTP.Offline = true
+	if !v.AvailableLocally(o) {
+		t.Fatalf("AvailableLocally() = false with cached artifact and signature")
+	}
+	cp = t.TempDir() + "/"
+	tx = Begin(cp, o)
+	tx.Install(v)
+	if err := tx.Apply(context.Background(), 1, nil, nil); err != nil {
+		t.Errorf("Apply() offline error = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestTransaction_Apply(t *testing.T) {
+	local := func(v Version) Version {
+		v.Path = testPath + "/" + v.Path
+		return v
+	}
+	missing := Version{Tag: "9.9.9", Path: testPath + "/tests/mocks/files/missing-9.9.9.txt"}
+	failWrite := func() error { return fmt.Errorf("disk full") }
+
+	tests := []struct {
+		name    string
+		install []Version
+		remove  []Version
+		write   func() error
+		opts    Options
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Can Install And Remove",
+			install: []Version{local(driverV2), local(extensionV1)},
+			remove:  []Version{driverV1},
+			want:    []string{"driver-0.2.0.txt", "extension-0.0.2.txt", "pro-0.0.1.txt"},
+		},
+		{
+			name:    "Can Reject Unsigned Version",
+			install: []Version{local(driverV2)},
+			remove:  []Version{driverV1},
+			opts:    Options{RequireSignatures: true},
+			want:    []string{"driver-0.0.1.txt", "pro-0.0.1.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "Can Roll Back Failed Download",
+			install: []Version{local(driverV2), missing},
+			remove:  []Version{driverV1},
+			want:    []string{"driver-0.0.1.txt", "pro-0.0.1.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "Can Roll Back Failed Write",
+			install: []Version{local(driverV2)},
+			remove:  []Version{driverV1},
+			write:   failWrite,
+			want:    []string{"driver-0.0.1.txt", "pro-0.0.1.txt"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := t.TempDir() + "/"
+			os.WriteFile(cp+"driver-0.0.1.txt", []byte("DriverSHA256"), 0664)
+			os.WriteFile(cp+"pro-0.0.1.txt", []byte("Pro"), 0664)
+			manifest := cp + "liquibase.json"
+			os.WriteFile(manifest, []byte("before"), 0664)
+
+			tx := Begin(cp, tt.opts)
+			tx.Install(tt.install...)
+			tx.Remove(tt.remove...)
+			if err := tx.Track(manifest); err != nil {
+				t.Fatal(err)
+			}
+			write := tt.write
+			if write == nil {
+				write = func() error { return os.WriteFile(manifest, []byte("after"), 0664) }
+			}
+			err := tx.Apply(context.Background(), 2, nil, write)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []string
+			entries, _ := os.ReadDir(cp)
+			for _, e := range entries {
+				if e.Name() != "liquibase.json" {
+					got = append(got, e.Name())
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Classpath = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && len(tx.Unverified()) != len(tt.install) {
+				t.Errorf("Unverified() = %v, want every unsigned version", tx.Unverified())
+			}
+			b, _ := os.ReadFile(manifest)
+			if tt.wantErr && string(b) != "before" {
+				t.Errorf("Expected liquibase.json to be restored but got %s", b)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package packages
 
 import (
+	"context"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
-	"package-manager/internal/app/utils"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -23,13 +29,6 @@ var driverV2 = Version{
 	CheckSum:      "",
 	LiquibaseCore: "4.16.2",
 }
-var driverV3 = Version{
-	Tag:           "0.2.0",
-	Path:          "tests/mocks/files/driver-0.2.0.txt",
-	Algorithm:     "MD5",
-	CheckSum:      "",
-	LiquibaseCore: "4.16.2",
-}
 var extensionV1 = Version{
 	Tag:           "0.0.2",
 	Path:          "tests/mocks/files/extension-0.0.2.txt",
@@ -171,111 +170,125 @@ func TestVersion_PathIsHttp(t *testing.T) {
 	}
 }
 
-func TestVersion_calcChecksum(t *testing.T) {
+func TestClasspathExists(t *testing.T) {
 	type args struct {
-		b []byte
+		cp string
 	}
+	pwd, _ := os.Getwd()
 	tests := []struct {
-		name    string
-		version Version
-		args    args
-		want    string
+		name string
+		args args
+		want bool
 	}{
 		{
-			name:    "Can Calc Checksum SHA1",
-			version: driverV2,
-			args:    args{[]byte("DriverSHA1")},
-			want:    "70daefe06dd19c073920273e02cfc712951795ea",
+			name: "Classpath Exists",
+			args: args{cp: pwd + "/../../../tests/mocks/classpath"},
+			want: true,
 		},
 		{
-			name:    "Can Calc Checksum SHA256",
-			version: driverV1,
-			args:    args{[]byte("DriverSHA256")},
-			want:    "94c74ea180983e2ec16451fed233c9f6d3d47572133cae84a0adc7c9fd7e1dd4",
+			name: "Classpath Does Not Exists",
+			args: args{cp: "./tests/mocks/not_classpath"},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			v := Version{
-				Tag:           tt.version.Tag,
-				Path:          tt.version.Path,
-				Algorithm:     tt.version.Algorithm,
-				CheckSum:      tt.version.CheckSum,
-				LiquibaseCore: tt.version.LiquibaseCore,
-			}
-			if got := v.calcChecksum(tt.args.b); got != tt.want {
-				t.Errorf("calcChecksum() = %v, want %v", got, tt.want)
+			if got := ClasspathExists(tt.args.cp); got != tt.want {
+				t.Errorf("ClasspathExists(%v) = %v, want %v", tt.args.cp, got, tt.want)
 			}
 		})
 	}
 }
 
-func TestVersion_calcUnknownChecksum(t *testing.T) {
-	type args struct {
-		b []byte
-	}
+func TestVersion_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("DriverSHA1"))
+	}))
+	defer srv.Close()
+	o := Options{Cache: cache.Cache{Dir: t.TempDir()}}
+
 	tests := []struct {
-		name    string
-		version Version
-		args    args
-		want    string
+		name     string
+		checksum string
+		want     []string
+		wantErr  bool
 	}{
 		{
-			name:    "UnknownChecksum",
-			version: driverV3,
-			args:    args{[]byte("DriverMD5")},
-			want:    "Unknown Algorithm.",
+			name:     "Can Download Verified Jar",
+			checksum: "70daefe06dd19c073920273e02cfc712951795ea",
+			want:     []string{"driver-0.2.0.jar"},
+		},
+		{
+			name:     "Can Discard Jar With Bad Checksum",
+			checksum: "0000000000000000000000000000000000000000",
+			want:     nil,
+			wantErr:  true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			v := Version{
-				Tag:           tt.version.Tag,
-				Path:          tt.version.Path,
-				Algorithm:     tt.version.Algorithm,
-				CheckSum:      tt.version.CheckSum,
-				LiquibaseCore: tt.version.LiquibaseCore,
+			cp := t.TempDir() + "/"
+			v := Version{Tag: "0.2.0", Path: srv.URL + "/driver-0.2.0.jar", Algorithm: "SHA1", CheckSum: tt.checksum}
+			err := v.Download(context.Background(), o, cp, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Download() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if os.Getenv("BE_CRASHER") == "1" {
-				v.calcChecksum(tt.args.b)
-				return
+			files, _ := utils.ReadDir(cp)
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Name())
 			}
-			cmd := exec.Command(os.Args[0], "-test.run=TestVersion_calcUnknownChecksum")
-			cmd.Env = append(os.Environ(), "BE_CRASHER=1")
-			err := cmd.Run()
-			if e, ok := err.(*exec.ExitError); ok && !e.Success() {
-				return
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classpath = %v, want %v", got, tt.want)
 			}
-			t.Fatalf("process ran with err %v, want exit status 1", err)
 		})
 	}
 }
 
-func TestClasspathExists(t *testing.T) {
-	type args struct {
-		cp string
+func TestVersion_AvailableLocally(t *testing.T) {
+	o := Options{Cache: cache.Cache{Dir: t.TempDir()}}
+	const sum = "70daefe06dd19c073920273e02cfc712951795ea"
+	o.Cache.Put("SHA1", sum, "driver-0.2.0.jar", strings.NewReader("DriverSHA1"))
+	v := Version{Tag: "0.2.0", Path: "https://example.com/driver-0.2.0.jar", Algorithm: "SHA1", CheckSum: sum}
+	if !v.AvailableLocally(o) {
+		t.Errorf("AvailableLocally() = false for cached version")
 	}
-	pwd, _ := os.Getwd()
+	o.MinAlgorithm = "SHA256"
+	if v.AvailableLocally(o) {
+		t.Errorf("AvailableLocally() = true for cached version below the minimum algorithm")
+	}
+}
+
+func TestVersion_Verify(t *testing.T) {
+	const sum = "70daefe06dd19c073920273e02cfc712951795ea" // SHA1 of "DriverSHA1"
+	// SHA512 of "DriverSHA1"
+	const sum512 = "3262f3191ece0c830a6fd6a816d28f746bbd9833b3eeb88197d6cdbdd6cd222347f25d81daf0381cf937ead83d56d78f2875700a79b484b398ddbaa7120e88a6"
+	cp := t.TempDir() + "/"
+	os.WriteFile(cp+"driver-0.2.0.txt", []byte("DriverSHA1"), 0664)
+	os.WriteFile(cp+"tampered-0.2.0.txt", []byte("Tampered"), 0664)
+	os.WriteFile(cp+"broken-0.2.0.jar", []byte("DriverSHA1"), 0664)
+
 	tests := []struct {
-		name string
-		args args
-		want bool
+		name    string
+		version Version
+		min     string
+		want    error
 	}{
-		{
-			name: "Classpath Exists",
-			args: args{cp: pwd + "/../../../tests/mocks/classpath"},
-			want: true,
-		},
-		{
-			name: "Classpath Does Not Exists",
-			args: args{cp: "./tests/mocks/not_classpath"},
-			want: false,
-		},
+		{name: "Can Verify Checksum", version: Version{Path: "driver-0.2.0.txt", Algorithm: "SHA1", CheckSum: sum}},
+		{name: "Can Detect Tampered File", version: Version{Path: "tampered-0.2.0.txt", Algorithm: "SHA1", CheckSum: sum}, want: errors.ErrChecksumMismatch},
+		{name: "Can Detect Corrupt Jar", version: Version{Path: "broken-0.2.0.jar", Algorithm: "SHA1", CheckSum: sum}, want: errors.ErrCorrupt},
+		{name: "Can Detect Unknown Algorithm", version: Version{Path: "driver-0.2.0.txt", Algorithm: "MD5"}, want: errors.ErrUnknownAlgorithm},
+		{name: "Can Detect Malformed Checksum", version: Version{Path: "driver-0.2.0.txt", Algorithm: "SHA1", CheckSum: "../../.."}, want: errors.ErrChecksumMismatch},
+		{name: "Can Verify SHA512", version: Version{Path: "driver-0.2.0.txt", Algorithm: "SHA1", CheckSum: sum, Checksums: map[string]string{"SHA512": sum512}}},
+		{name: "Verifies Strongest Checksum", version: Version{Path: "driver-0.2.0.txt", Algorithm: "SHA1", CheckSum: sum, Checksums: map[string]string{"SHA512": sum}}, want: errors.ErrChecksumMismatch},
+		{name: "Can Reject Weak Checksum", version: Version{Path: "driver-0.2.0.txt", Algorithm: "SHA1", CheckSum: sum}, min: "SHA256", want: errors.ErrWeakChecksum},
+		{name: "Accepts Strong Checksum", version: Version{Path: "driver-0.2.0.txt", Checksums: map[string]string{"sha512": sum512}}, min: "SHA256"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := ClasspathExists(tt.args.cp); got != tt.want {
-				t.Errorf("ClasspathExists(%v) = %v, want %v", tt.args.cp, got, tt.want)
+			err := tt.version.Verify(cp, Options{MinAlgorithm: tt.min})
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
 			}
 		})
 	}
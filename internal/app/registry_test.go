@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistries_Load(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	key := base64.StdEncoding.EncodeToString(priv.Seed())
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	unsigned := filepath.Join(dir, "unsigned.json")
+	os.WriteFile(first, []byte(`[{"name":"alpha","category":"driver","versions":[]}]`), 0664)
+	os.WriteFile(second, []byte(`[{"name":"alpha","category":"extension","versions":[]},{"name":"beta","category":"extension","versions":[]}]`), 0664)
+	os.WriteFile(unsigned, []byte(`[{"name":"gamma","category":"driver","versions":[]}]`), 0664)
+	SignManifest(first, key)
+	SignManifest(second, key)
+	keys := []ed25519.PublicKey{pub}
+
+	ps, err := Registries{Sources: []string{first, "file://" + second}, Keys: keys}.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(ps) != 2 {
+		t.Fatalf("Load() = %v, want 2 packages", ps)
+	}
+	if a := ps.GetByName("alpha"); a.Category != "driver" || a.Registry != first {
+		t.Errorf("alpha = %v, want driver from %s", a, first)
+	}
+	if b := ps.GetByName("beta"); b.Registry != "file://"+second {
+		t.Errorf("beta registry = %s, want file://%s", b.Registry, second)
+	}
+
+	tests := []struct {
+		name string
+		r    Registries
+		want error
+	}{
+		{name: "Can Detect Missing Registry", r: Registries{Sources: []string{filepath.Join(dir, "missing.json")}, Keys: keys}, want: os.ErrNotExist},
+		{name: "Can Reject Unsigned Registry", r: Registries{Sources: []string{unsigned}, Keys: keys}, want: errors.ErrBadSignature},
+		{name: "Can Reject Untrusted Registry", r: Registries{Sources: []string{first}}, want: errors.ErrBadSignature},
+		{name: "Can Allow Insecure Registry", r: Registries{Sources: []string{unsigned}, Insecure: []string{unsigned}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.r.Load(context.Background())
+			if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("Load() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistries_Remote(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "packages.json")
+	os.WriteFile(manifest, []byte(`[{"name":"alpha","category":"driver","versions":[]}]`), 0664)
+	SignManifest(manifest, base64.StdEncoding.EncodeToString(priv.Seed()))
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	r := Registries{Sources: []string{srv.URL + "/packages.json"}, Keys: []ed25519.PublicKey{pub}, Dir: filepath.Join(dir, "stored")}
+
+	r.HTTP.Offline = true
+	if _, err := r.Load(context.Background()); !errors.Is(err, errors.ErrOffline) {
+		t.Fatalf("Load() offline without stored copy error = %v, want %v", err, errors.ErrOffline)
+	}
+	r.HTTP.Offline = false
+	if _, err := r.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Later commands and offline runs use the stored copy
+	srv.Close()
+	r.HTTP.Offline = true
+	ps, err := r.Load(context.Background())
+	if err != nil || ps.GetByName("alpha").Name != "alpha" {
+		t.Fatalf("Load() stored copy = %v, error = %v", ps, err)
+	}
+	if err = r.Update(context.Background()); !errors.Is(err, errors.ErrOffline) {
+		t.Errorf("Update() offline error = %v, want %v", err, errors.ErrOffline)
+	}
+
+	// A stored copy changed on disk no longer verifies
+	os.WriteFile(r.stored(r.Sources[0]), []byte(`[]`), 0664)
+	if _, err = r.Load(context.Background()); !errors.Is(err, errors.ErrBadSignature) {
+		t.Errorf("Load() tampered stored copy error = %v, want %v", err, errors.ErrBadSignature)
+	}
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Credential authentication sent to a repository host
+type Credential struct {
+	Host     string // host name, with port when it must match exactly, required
+	Username string
+	Password string
+	Token    string // bearer token, sent instead of basic auth when set
+}
+
+// ParseCredential host=user:password for basic auth or host=token for a bearer token.
+// Errors never include s, it holds a secret.
+func ParseCredential(s string) (Credential, error) {
+	host, secret, ok := strings.Cut(s, "=")
+	if !ok || host == "" || secret == "" {
+		return Credential{}, fmt.Errorf("invalid credential, expected host=user:password or host=token")
+	}
+	if user, pass, ok := strings.Cut(secret, ":"); ok {
+		return Credential{Host: host, Username: user, Password: pass}, nil
+	}
+	return Credential{Host: host, Token: secret}, nil
+}
+
+// NetrcPath location of the .netrc file, $NETRC or ~/.netrc
+func NetrcPath() string {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// ReadNetrc machine entries of a .netrc file. The default entry is ignored, credentials are only sent to named hosts.
+func ReadNetrc(path string) ([]Credential, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r []Credential
+	var c *Credential
+	macro := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// Macro definitions run until the next empty line
+		if macro {
+			macro = strings.TrimSpace(sc.Text()) != ""
+			continue
+		}
+		fields := strings.Fields(sc.Text())
+		for i := 0; i < len(fields); i++ {
+			next := func() string {
+				if i+1 < len(fields) {
+					i++
+					return fields[i]
+				}
+				return ""
+			}
+			switch fields[i] {
+			case "machine":
+				r = append(r, Credential{Host: next()})
+				c = &r[len(r)-1]
+			case "default":
+				c = nil
+			case "login":
+				if c != nil {
+					c.Username = next()
+				}
+			case "password":
+				if c != nil {
+					c.Password = next()
+				}
+			case "account":
+				next()
+			case "macdef":
+				macro = true
+				i = len(fields)
+			}
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// authorize add the credential matching the request host, only over https unless InsecureAuth.
+// URLs with user info keep their own.
+func (h HTTPUtil) authorize(req *http.Request) {
+	if req.URL.User != nil || !h.secure(req.URL) {
+		return
+	}
+	for _, c := range h.Credentials {
+		if c.Host != req.URL.Host && c.Host != req.URL.Hostname() {
+			continue
+		}
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		} else if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Password)
+		}
+		return
+	}
+}
+
+// secure whether credentials may be sent to u
+func (h HTTPUtil) secure(u *url.URL) bool {
+	return u.Scheme == "https" || h.InsecureAuth
+}
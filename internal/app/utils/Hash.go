@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// NewHash create hash for checksum algorithm name used in packages.json
+func NewHash(alg string) (hash.Hash, error) {
+	switch strings.ToUpper(alg) {
+	case "SHA1":
+		return sha1.New(), nil
+	case "SHA256":
+		return sha256.New(), nil
+	case "SHA512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown algorithm %s", alg)
+	}
+}
+
+// Strength rank of checksum algorithm, higher is stronger and 0 is unknown
+func Strength(alg string) int {
+	switch strings.ToUpper(alg) {
+	case "SHA1":
+		return 1
+	case "SHA256":
+		return 2
+	case "SHA512":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ValidChecksum sum is lower case hex of the digest length of alg
+func ValidChecksum(alg string, sum string) bool {
+	h, err := NewHash(alg)
+	if err != nil || len(sum) != h.Size()*2 {
+		return false
+	}
+	for _, c := range sum {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// FileChecksum hex encoded checksum of file contents
+func FileChecksum(alg string, path string) (string, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
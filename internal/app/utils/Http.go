@@ -1,26 +1,291 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	lpmerrors "github.com/liquibase/liquibase-package-manager/internal/app/errors"
 	"io"
+	"net"
 	"net/http"
-	"package-manager/internal/app/errors"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
+// DefaultHTTP timeouts and retries used by an HTTPUtil that sets none of them
+var DefaultHTTP = HTTPUtil{
+	ConnectTimeout: 10 * time.Second,
+	ReadTimeout:    30 * time.Second,
+	Retries:        3,
+	Backoff:        500 * time.Millisecond,
+}
+
 // HTTPUtil struct
-type HTTPUtil struct{}
+type HTTPUtil struct {
+	ConnectTimeout time.Duration // dial and TLS handshake timeout
+	ReadTimeout    time.Duration // maximum wait for response headers and between body reads
+	Retries        int           // attempts after the first on 5xx responses and connection failures
+	Backoff        time.Duration // initial retry delay, doubled after every attempt
+	Offline        bool          // refuse all network access
+	Proxy          string        // proxy URL for all requests, HTTPS_PROXY and HTTP_PROXY are used when empty
+	Credentials    []Credential  // per-host authentication, first match wins, only sent over https
+	InsecureAuth   bool          // also send Credentials over plain http
+}
+
+// StatusError unexpected HTTP response status
+type StatusError struct {
+	URL    string
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unable to download from %s: server returned %s", e.URL, e.Status)
+}
+
+// Unwrap classify status errors as network errors
+func (e *StatusError) Unwrap() error {
+	return lpmerrors.ErrNetwork
+}
 
 // Get contents from URL as bytes
-func (h HTTPUtil) Get(url string) []byte {
-	client := http.Client{}
-	r, err := client.Get(url)
+func (h HTTPUtil) Get(url string) ([]byte, error) {
+	return h.Read(context.Background(), url)
+}
+
+// Read contents from URL as bytes, returning an error on failure
+func (h HTTPUtil) Read(ctx context.Context, url string) ([]byte, error) {
+	body, _, err := h.Fetch(ctx, url)
 	if err != nil {
-		errors.Exit("Unable to download from "+url, 1)
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// Fetch open streaming response body from URL with its content length, -1 when unknown.
+// Failed requests are retried with exponential backoff and interrupted bodies resume with a Range request.
+func (h HTTPUtil) Fetch(ctx context.Context, url string) (io.ReadCloser, int64, error) {
+	if h.Offline {
+		return nil, 0, lpmerrors.Newf(lpmerrors.ErrOffline, "unable to download from %s in offline mode", url)
+	}
+	h = h.withDefaults()
+	r := &resumingReader{h: h, ctx: ctx, url: url, client: h.client()}
+	if err := r.open(); err != nil {
+		return nil, 0, err
+	}
+	return r, r.size, nil
+}
+
+func (h HTTPUtil) withDefaults() HTTPUtil {
+	if h.ConnectTimeout == 0 && h.ReadTimeout == 0 && h.Retries == 0 && h.Backoff == 0 {
+		h.ConnectTimeout, h.ReadTimeout = DefaultHTTP.ConnectTimeout, DefaultHTTP.ReadTimeout
+		h.Retries, h.Backoff = DefaultHTTP.Retries, DefaultHTTP.Backoff
+	}
+	return h
+}
+
+// ValidateProxy check proxy is empty or an absolute URL
+func ValidateProxy(proxy string) error {
+	if proxy == "" {
+		return nil
+	}
+	if u, err := url.Parse(proxy); err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy %s, expected a URL such as http://proxy.example.com:3128", proxy)
+	}
+	return nil
+}
 
+func (h HTTPUtil) client() *http.Client {
+	dialer := &net.Dialer{Timeout: h.ConnectTimeout}
+	proxy := http.ProxyFromEnvironment
+	if u, err := url.Parse(h.Proxy); h.Proxy != "" && err == nil {
+		proxy = http.ProxyURL(u)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   h.ConnectTimeout,
+			ResponseHeaderTimeout: h.ReadTimeout,
+			DisableKeepAlives:     true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			// Credentials copied from the original request must not follow a redirect to plain http
+			if !h.secure(req.URL) {
+				req.Header.Del("Authorization")
+			}
+			return nil
+		},
 	}
-	defer r.Body.Close()
-	body, err := io.ReadAll(r.Body)
+}
+
+// resumingReader response body that transparently retries and resumes interrupted downloads
+type resumingReader struct {
+	h       HTTPUtil
+	ctx     context.Context
+	url     string
+	client  *http.Client
+	body    io.ReadCloser
+	cancel  context.CancelFunc
+	idle    *time.Timer
+	size    int64
+	offset  int64
+	attempt int
+	mu      sync.Mutex
+}
+
+// open request the remaining bytes from offset, retrying on transient failures
+func (r *resumingReader) open() error {
+	for {
+		err := r.request()
+		if err == nil {
+			return nil
+		}
+		if !retryable(err) || r.attempt >= r.h.Retries {
+			return err
+		}
+		if err = r.wait(); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *resumingReader) request() error {
+	ctx, cancel := context.WithCancel(r.ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	r.h.authorize(req)
+	if r.offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	}
+	resp, err := r.client.Do(req)
 	if err != nil {
-		errors.Exit(err.Error(), 1)
+		cancel()
+		return lpmerrors.Newf(lpmerrors.ErrNetwork, "unable to download from %s: %w", r.url, err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		cancel()
+		return &StatusError{URL: r.url, Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	body := resp.Body
+	if r.offset == 0 {
+		r.size = resp.ContentLength
+	} else if resp.StatusCode == http.StatusOK {
+		// Server ignored the Range header, skip the bytes already delivered
+		if _, err = io.CopyN(io.Discard, body, r.offset); err != nil {
+			body.Close()
+			cancel()
+			return lpmerrors.Newf(lpmerrors.ErrNetwork, "unable to resume download from %s: %w", r.url, err)
+		}
+	} else if !strings.HasPrefix(resp.Header.Get("Content-Range"), "bytes "+strconv.FormatInt(r.offset, 10)+"-") {
+		body.Close()
+		cancel()
+		return lpmerrors.Newf(lpmerrors.ErrNetwork, "unable to resume download from %s: unexpected Content-Range %q", r.url, resp.Header.Get("Content-Range"))
+	}
+	r.mu.Lock()
+	r.body, r.cancel = body, cancel
+	r.idle = time.AfterFunc(r.h.ReadTimeout, cancel)
+	r.mu.Unlock()
+	return nil
+}
+
+// wait back off before the next attempt
+func (r *resumingReader) wait() error {
+	delay := r.h.Backoff << r.attempt
+	r.attempt++
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// Read stream body, resuming from the current offset when the connection fails
+func (r *resumingReader) Read(p []byte) (int, error) {
+	for {
+		// Close may run concurrently, only use the body and timer it has not released
+		r.mu.Lock()
+		body, idle := r.body, r.idle
+		r.mu.Unlock()
+		if body == nil {
+			return 0, os.ErrClosed
+		}
+		n, err := body.Read(p)
+		r.offset += int64(n)
+		idle.Reset(r.h.ReadTimeout)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if r.ctx.Err() != nil {
+			return n, r.ctx.Err()
+		}
+		r.closeBody()
+		if r.attempt >= r.h.Retries {
+			return n, lpmerrors.Newf(lpmerrors.ErrNetwork, "unable to download from %s: %w", r.url, err)
+		}
+		if werr := r.wait(); werr != nil {
+			return n, werr
+		}
+		if oerr := r.open(); oerr != nil {
+			return n, oerr
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close release response body
+func (r *resumingReader) Close() error {
+	return r.closeBody()
+}
+
+func (r *resumingReader) closeBody() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.body == nil {
+		return nil
+	}
+	r.idle.Stop()
+	err := r.body.Close()
+	r.cancel()
+	r.body = nil
+	return err
+}
+
+// retryable server errors, rate limiting and connection failures. Invalid URLs, unsupported schemes
+// and other errors that would fail again are not retried.
+func retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= 500 || se.Code == http.StatusTooManyRequests || se.Code == http.StatusRequestTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// url.Error satisfies net.Error itself, classify the failure it wraps
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
 	}
-	return body
+	var ne net.Error
+	return errors.As(err, &ne)
 }
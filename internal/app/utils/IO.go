@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"archive/zip"
+	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
 )
 
@@ -20,3 +24,55 @@ func ReadDir(dirname string) ([]fs.FileInfo, error) {
 	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
 	return list, nil
 }
+
+// WriteAtomic stream r to a temp file next to dst and rename it into place once verify passes.
+// The temp file is removed on any failure, so dst is never left partially written.
+func WriteAtomic(dst string, r io.Reader, verify func() error) (err error) {
+	dir, name := filepath.Split(dst)
+	tmp, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to access classpath located at %s", dir)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = io.Copy(tmp, r); err != nil {
+		return fmt.Errorf("unable to write %s: %w", name, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if verify != nil {
+		if err = verify(); err != nil {
+			return err
+		}
+	}
+	if err = os.Chmod(tmp.Name(), 0664); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// CheckZip read every entry of a zip or jar archive, failing on a broken structure or CRC mismatch
+func CheckZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		_, err = io.Copy(io.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
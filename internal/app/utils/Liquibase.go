@@ -41,7 +41,8 @@ func LoadLiquibase(hp string) Liquibase {
 		if f.Name == "liquibase.build.properties" {
 			file, err := f.Open()
 			if err != nil {
-				log.Fatal(err)
+				log.Printf("Error reading liquibase.build.properties: %v. Falling back to version '0.0.0'.", err)
+				break
 			}
 			defer file.Close()
 
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress renders size, rate and ETA for concurrent transfers
+type Progress struct {
+	mu     sync.Mutex
+	out    io.Writer
+	tty    bool
+	bars   []*Bar
+	drawn  int
+	redraw time.Time
+}
+
+// Bar progress of a single transfer
+type Bar struct {
+	p       *Progress
+	name    string
+	total   int64
+	current int64
+	start   time.Time
+	status  string
+}
+
+// NewProgress create progress renderer, redrawing in place when out is a terminal
+func NewProgress(out *os.File) *Progress {
+	p := &Progress{out: out}
+	if fi, err := out.Stat(); err == nil {
+		p.tty = fi.Mode()&os.ModeCharDevice != 0
+	}
+	return p
+}
+
+// Add start tracking transfer of total bytes, total is -1 when unknown
+func (p *Progress) Add(name string, total int64) *Bar {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b := &Bar{p: p, name: name, total: total, start: time.Now()}
+	p.bars = append(p.bars, b)
+	p.render(true)
+	return b
+}
+
+// Write count transferred bytes, used as io.TeeReader target
+func (b *Bar) Write(d []byte) (int, error) {
+	if b == nil {
+		return len(d), nil
+	}
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+	b.current += int64(len(d))
+	b.p.render(false)
+	return len(d), nil
+}
+
+// Reset restart transfer from offset, e.g. after a retry
+func (b *Bar) Reset(offset int64) {
+	if b == nil {
+		return
+	}
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+	b.current = offset
+	b.start = time.Now()
+}
+
+// Done mark transfer finished with status message, e.g. "done" or an error
+func (b *Bar) Done(status string) {
+	if b == nil {
+		return
+	}
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+	b.status = status
+	if !b.p.tty {
+		fmt.Fprintln(b.p.out, b.line())
+		return
+	}
+	b.p.render(true)
+}
+
+// render redraw all bars in place, throttled unless forced; caller holds lock
+func (p *Progress) render(force bool) {
+	if !p.tty || (!force && time.Since(p.redraw) < 100*time.Millisecond) {
+		return
+	}
+	p.redraw = time.Now()
+	var sb strings.Builder
+	if p.drawn > 0 {
+		sb.WriteString(fmt.Sprintf("\033[%dA", p.drawn))
+	}
+	for _, b := range p.bars {
+		sb.WriteString("\033[2K" + b.line() + "\n")
+	}
+	p.drawn = len(p.bars)
+	fmt.Fprint(p.out, sb.String())
+}
+
+func (b *Bar) line() string {
+	elapsed := time.Since(b.start).Seconds()
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(b.current) / elapsed
+	}
+	size := FormatBytes(b.current)
+	if b.total > 0 {
+		size += " / " + FormatBytes(b.total)
+	}
+	status := b.status
+	if status == "" {
+		status = "ETA --"
+		if b.total > 0 && rate > 0 {
+			eta := time.Duration(float64(b.total-b.current) / rate * float64(time.Second))
+			status = "ETA " + eta.Round(time.Second).String()
+		}
+	}
+	return fmt.Sprintf("%-48s %-22s %-12s %s", b.name, size, FormatBytes(int64(rate))+"/s", status)
+}
+
+// FormatBytes human readable byte size
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
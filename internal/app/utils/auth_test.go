@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHTTPUtil_Credentials(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	tests := []struct {
+		name        string
+		credentials []Credential
+		insecure    bool
+		url         string
+		want        string
+	}{
+		{name: "Can Send Basic Auth", credentials: []Credential{{Host: u.Hostname(), Username: "user", Password: "secret"}}, insecure: true, url: srv.URL, want: "Basic dXNlcjpzZWNyZXQ="},
+		{name: "Can Send Bearer Token", credentials: []Credential{{Host: u.Host, Token: "abc"}}, insecure: true, url: srv.URL, want: "Bearer abc"},
+		{name: "Skips Plain HTTP", credentials: []Credential{{Host: u.Host, Token: "abc"}}, url: srv.URL},
+		{name: "Skips Other Hosts", credentials: []Credential{{Host: "repo.example.com", Token: "abc"}}, insecure: true, url: srv.URL},
+		{name: "Skips Credentials Without Host", credentials: []Credential{{Token: "abc"}}, insecure: true, url: srv.URL},
+		{name: "Keeps URL User Info", credentials: []Credential{{Host: u.Host, Token: "abc"}}, insecure: true, url: "http://own:pw@" + u.Host, want: "Basic b3duOnB3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := testHTTP
+			h.Credentials, h.InsecureAuth, got = tt.credentials, tt.insecure, ""
+			if _, err := h.Read(context.Background(), tt.url); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCredential(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Credential
+		wantErr bool
+	}{
+		{name: "Can Parse Basic Auth", s: "nexus.example.com=user:pa:ss", want: Credential{Host: "nexus.example.com", Username: "user", Password: "pa:ss"}},
+		{name: "Can Parse Token", s: "nexus.example.com:8443=abc", want: Credential{Host: "nexus.example.com:8443", Token: "abc"}},
+		{name: "Can Detect Missing Secret", s: "nexus.example.com", wantErr: true},
+		{name: "Can Detect Missing Host", s: "=user:s3cret", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCredential(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), tt.s) {
+				t.Errorf("ParseCredential() error %q contains the input", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCredential() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNetrc(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	os.WriteFile(path, []byte(`default login anonymous password guest
+machine nexus.example.com
+  login deploy
+  password s3cret
+macdef init
+  machine ignored.example.com login x password y
+
+machine repo.example.com login ci password token account build
+`), 0600)
+	got, err := ReadNetrc(path)
+	if err != nil {
+		t.Fatalf("ReadNetrc() error = %v", err)
+	}
+	want := []Credential{
+		{Host: "nexus.example.com", Username: "deploy", Password: "s3cret"},
+		{Host: "repo.example.com", Username: "ci", Password: "token"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadNetrc() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var testHTTP = HTTPUtil{ConnectTimeout: time.Second, ReadTimeout: time.Second, Retries: 2, Backoff: time.Millisecond}
+
+func TestHTTPUtil_Read(t *testing.T) {
+	const content = "0123456789abcdefghij"
+	tests := []struct {
+		name     string
+		handler  func(calls int32, w http.ResponseWriter, r *http.Request)
+		want     string
+		wantCode int
+	}{
+		{
+			name: "Can Read Body",
+			handler: func(calls int32, w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(content))
+			},
+			want: content,
+		},
+		{
+			name: "Can Retry Server Errors",
+			handler: func(calls int32, w http.ResponseWriter, r *http.Request) {
+				if calls < 2 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Write([]byte(content))
+			},
+			want: content,
+		},
+		{
+			name: "Can Fail On Not Found",
+			handler: func(calls int32, w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "Can Resume Interrupted Download",
+			handler: func(calls int32, w http.ResponseWriter, r *http.Request) {
+				if rng := r.Header.Get("Range"); rng != "" {
+					from, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+					w.Header().Set("Content-Range", "bytes "+strconv.Itoa(from)+"-"+strconv.Itoa(len(content)-1)+"/"+strconv.Itoa(len(content)))
+					w.WriteHeader(http.StatusPartialContent)
+					w.Write([]byte(content[from:]))
+					return
+				}
+				// Promise the full body but drop the connection half way
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.Write([]byte(content[:10]))
+				w.(http.Flusher).Flush()
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			},
+			want: content,
+		},
+		{
+			name: "Can Resume When Range Is Ignored",
+			handler: func(calls int32, w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				if calls > 1 {
+					w.Write([]byte(content))
+					return
+				}
+				w.Write([]byte(content[:10]))
+				w.(http.Flusher).Flush()
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			},
+			want: content,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handler(atomic.AddInt32(&calls, 1), w, r)
+			}))
+			defer srv.Close()
+
+			got, err := testHTTP.Read(context.Background(), srv.URL)
+			var se *StatusError
+			if tt.wantCode != 0 {
+				if !errors.As(err, &se) || se.Code != tt.wantCode {
+					t.Fatalf("Read() error = %v, want status %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Read() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPUtil_Offline(t *testing.T) {
+	h := testHTTP
+	h.Offline = true
+	if _, err := h.Read(context.Background(), "http://127.0.0.1:1"); err == nil {
+		t.Fatalf("Expected offline mode to refuse downloads")
+	}
+}
+
+func TestHTTPUtil_NoRetryOnInvalidURL(t *testing.T) {
+	slow := HTTPUtil{ConnectTimeout: time.Second, ReadTimeout: time.Second, Retries: 3, Backoff: time.Second}
+	for _, u := range []string{"ftp://example.com/driver.jar", "http://[::1"} {
+		start := time.Now()
+		if _, err := slow.Read(context.Background(), u); err == nil {
+			t.Errorf("Read(%s) expected error", u)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Errorf("Read(%s) retried a permanent failure", u)
+		}
+	}
+}
+
+func TestHTTPUtil_RetryConnectionRefused(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	u := srv.URL
+	srv.Close()
+	var se *StatusError
+	if _, err := testHTTP.Read(context.Background(), u); err == nil || errors.As(err, &se) {
+		t.Fatalf("Read() error = %v, want connection failure", err)
+	}
+	if !retryable(&url.Error{Op: "Get", URL: u, Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}) {
+		t.Errorf("connection refused should be retried")
+	}
+}
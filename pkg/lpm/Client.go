@@ -0,0 +1,810 @@
+// Package lpm installs and manages Liquibase extensions, drivers and utilities from Go.
+//
+// It is the library behind the lpm command line tool:
+//
+//	c, err := lpm.New(lpm.Options{Home: "/opt/liquibase"})
+//	if err != nil {
+//		return err
+//	}
+//	res, err := c.Add(ctx, "liquibase-mongodb@^4.20")
+package lpm
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/liquibase/liquibase-package-manager/internal/app"
+	"github.com/liquibase/liquibase-package-manager/internal/app/cache"
+	"github.com/liquibase/liquibase-package-manager/internal/app/dependencies"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"github.com/liquibase/liquibase-package-manager/internal/app/packages"
+	"github.com/liquibase/liquibase-package-manager/internal/app/utils"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Options client configuration, zero values match the lpm command line defaults
+type Options struct {
+	Home         string   // LIQUIBASE_HOME, required
+	Classpath    string   // directory packages are installed in, defaults to the liquibase.json classpath field, <Project>/liquibase_libs or <Home>/lib when Global
+	Global       bool     // install in <Home>/lib and do not track packages in liquibase.json
+	Project      string   // directory holding liquibase.json and liquibase.lock.json, defaults to the working directory
+	Manifest     string   // packages.json location as local path, file:// or http(s) URL, defaults to <Home>/lib/packages.json
+	Registries   []string // additional manifests in priority order, their packages take precedence over Manifest
+	Category     string   // only consider packages of category: extension, driver or utility
+	Jobs         int      // concurrent downloads, defaults to packages.DefaultJobs
+	Progress     *os.File // download progress output, nil disables progress reporting
+	SkipExisting bool     // Add skips packages that are already installed instead of failing
+	Replace      bool     // Add replaces a different installed version, including older ones, with the requested one
+
+	Offline            bool          // resolve packages only from local paths and the download cache
+	ConnectTimeout     time.Duration // timeout for establishing connections, defaults to 10s
+	ReadTimeout        time.Duration // timeout waiting for response data, defaults to 30s
+	Retries            int           // retries on server errors and connection failures, defaults to 3, negative disables retries
+	Proxy              string        // HTTP proxy URL, defaults to HTTPS_PROXY and HTTP_PROXY
+	Credentials        []Credential  // repository authentication, first matching host wins, only sent over https
+	InsecureAuth       bool          // also send Credentials over plain http
+	CacheDir           string        // download cache directory, defaults to the user cache directory
+	MinAlgorithm       string        // weakest checksum algorithm accepted: SHA1, SHA256 or SHA512, defaults to SHA1
+	Keyring            string        // file of OpenPGP public keys trusted in addition to the signers of published packages, unsigned packages are rejected when set
+	TrustedKeys        []string      // base64 ed25519 public keys trusted to sign Registries, in addition to the keys of the public manifest
+	InsecureRegistries []string      // Registries used without checking their signature
+}
+
+// Credential repository authentication for a host, basic auth with Username and Password or a bearer Token
+type Credential = utils.Credential
+
+// Client package operations against a Liquibase installation
+type Client struct {
+	opts       Options
+	liquibase  utils.Liquibase
+	packs      packages.Packages
+	install    packages.Options // download and verification settings derived from opts
+	registries app.Registries
+}
+
+// Package installed package
+type Package struct {
+	Name     string
+	Category string
+	Version  string
+	Filename string
+	Latest   string // newest version compatible with Liquibase
+	Registry string // manifest the package was loaded from
+}
+
+// Resolution package version selected for install
+type Resolution struct {
+	Name       string
+	Category   string
+	Version    string
+	Filename   string
+	Source     string // URL or local path of the artifact
+	RequiredBy string // package depending on this one, empty for requested packages
+	Installed  bool   // version is already present in classpath
+}
+
+// Result changes made to the classpath
+type Result struct {
+	Installed []Resolution // newly installed versions
+	Removed   []Resolution // uninstalled versions
+	Skipped   []Resolution // already installed versions left untouched
+	// Unverified installed versions whose signature was not checked, because they are unsigned or no keys are trusted
+	Unverified []Resolution
+}
+
+// OutdatedPackage installed package with a newer compatible version
+type OutdatedPackage struct {
+	Name      string
+	Category  string
+	Installed string
+	Latest    string
+	Target    string // version UpgradeWith installs, Latest unless bounded by UpgradeOptions
+}
+
+// Upgrade policies bounding how far UpgradeOptions.Policy lets a version move
+const (
+	Patch = packages.Patch // same major and minor version
+	Minor = packages.Minor // same major version
+	Major = packages.Major // any newer version
+)
+
+// UpgradeOptions selects what UpgradeWith changes, the zero value upgrades every outdated package to its latest version
+type UpgradeOptions struct {
+	Packages []string // only upgrade these installed packages
+	To       string   // version or range to upgrade to, requires exactly one package
+	Policy   string   // Patch, Minor or Major, unbounded when empty
+}
+
+// upgrade planned version change of an installed package
+type upgrade struct {
+	packages.Resolution
+	installed  packages.Version
+	constraint string // range to record in liquibase.json, the target tag when empty
+}
+
+// New client for the Liquibase installation in opts.Home, loading the package manifest
+func New(opts Options) (*Client, error) {
+	return NewContext(context.Background(), opts)
+}
+
+// NewContext like New, downloading remote manifests and registries until ctx is done
+func NewContext(ctx context.Context, opts Options) (*Client, error) {
+	if opts.Home == "" {
+		return nil, fmt.Errorf("Unable to locate Liquibase.")
+	}
+	opts.Home = withSeparator(opts.Home)
+	libpath := opts.Home + "lib" + string(os.PathSeparator)
+	if opts.Project == "" {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		opts.Project = pwd
+	}
+	if opts.Classpath == "" {
+		if opts.Global {
+			opts.Classpath = libpath
+		} else {
+			cp, err := projectClasspath(opts.Project)
+			if err != nil {
+				return nil, err
+			}
+			opts.Classpath = cp
+		}
+	}
+	opts.Classpath = withSeparator(opts.Classpath)
+	if opts.Jobs < 1 {
+		opts.Jobs = packages.DefaultJobs
+	}
+	install, err := installOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := manifestSource(opts.Manifest, libpath)
+	if err != nil {
+		return nil, err
+	}
+	b, err := app.ReadManifest(ctx, install.HTTP, source)
+	if err != nil {
+		return nil, err
+	}
+	base, err := app.LoadPackages(b)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := app.ParseManifestKeys(app.ManifestKeys + "\n" + strings.Join(opts.TrustedKeys, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	registries := app.Registries{
+		Sources:  opts.Registries,
+		Insecure: opts.InsecureRegistries,
+		Keys:     keys,
+		HTTP:     install.HTTP,
+		Dir:      libpath + app.RegistryDir,
+	}
+	packs, err := registries.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	packs = packs.Merge(base.WithRegistry(source))
+	if opts.Category != "" {
+		packs = packs.FilterByCategory(opts.Category)
+	}
+	return &Client{opts: opts, liquibase: utils.LoadLiquibase(opts.Home), packs: packs, install: install, registries: registries}, nil
+}
+
+// UpdateRegistries download the remote Registries again, later clients use the updated copies
+func (c *Client) UpdateRegistries(ctx context.Context) error {
+	return c.registries.Update(ctx)
+}
+
+// installOptions download and verification settings of opts, unset values take the defaults of the lpm command line
+func installOptions(opts Options) (packages.Options, error) {
+	h := utils.DefaultHTTP
+	h.Offline, h.Proxy, h.Credentials, h.InsecureAuth = opts.Offline, opts.Proxy, opts.Credentials, opts.InsecureAuth
+	if opts.ConnectTimeout > 0 {
+		h.ConnectTimeout = opts.ConnectTimeout
+	}
+	if opts.ReadTimeout > 0 {
+		h.ReadTimeout = opts.ReadTimeout
+	}
+	if opts.Retries > 0 {
+		h.Retries = opts.Retries
+	} else if opts.Retries < 0 {
+		h.Retries = 0
+	}
+	if err := utils.ValidateProxy(h.Proxy); err != nil {
+		return packages.Options{}, err
+	}
+	o := packages.Options{HTTP: h, Cache: cache.Cache{Dir: opts.CacheDir}, MinAlgorithm: opts.MinAlgorithm}
+	if o.MinAlgorithm != "" && utils.Strength(o.MinAlgorithm) == 0 {
+		return o, errors.Newf(errors.ErrUnknownAlgorithm, "unknown algorithm %s, expected SHA1, SHA256 or SHA512", o.MinAlgorithm)
+	}
+	kr, err := packages.ReadKeyring(app.PackageKeys)
+	if err != nil {
+		return o, fmt.Errorf("unable to read embedded package keys: %w", err)
+	}
+	o.Keyring = kr
+	if opts.Keyring != "" {
+		if kr, err = packages.LoadKeyring(opts.Keyring); err != nil {
+			return o, err
+		}
+		o.Keyring = append(o.Keyring, kr...)
+		o.RequireSignatures = true
+	}
+	return o, nil
+}
+
+// Classpath directory packages are installed in
+func (c *Client) Classpath() string {
+	return c.opts.Classpath
+}
+
+// LiquibaseVersion version of the Liquibase installation, empty when unknown
+func (c *Client) LiquibaseVersion() string {
+	if c.liquibase.Version == nil {
+		return ""
+	}
+	return c.liquibase.Version.String()
+}
+
+// List packages installed in classpath
+func (c *Client) List() ([]Package, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	var r []Package
+	for _, p := range c.packs.GetInstalled(files) {
+		v := p.GetInstalledVersion(files)
+		r = append(r, Package{
+			Name:     p.Name,
+			Category: p.Category,
+			Version:  v.Tag,
+			Filename: v.GetFilename(),
+			Latest:   p.GetLatestVersion(c.liquibase.Version).Tag,
+			Registry: p.Registry,
+		})
+	}
+	return r, nil
+}
+
+// Outdated installed packages with a newer version compatible with Liquibase
+func (c *Client) Outdated() ([]OutdatedPackage, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	var r []OutdatedPackage
+	for _, p := range c.packs.GetOutdated(c.liquibase.Version, files) {
+		latest := p.GetLatestVersion(c.liquibase.Version).Tag
+		r = append(r, OutdatedPackage{
+			Name:      p.Name,
+			Category:  p.Category,
+			Installed: p.GetInstalledVersion(files).Tag,
+			Latest:    latest,
+			Target:    latest,
+		})
+	}
+	return r, nil
+}
+
+// PlanUpgrade installed packages UpgradeWith changes with the same options, without installing anything
+func (c *Client) PlanUpgrade(opts UpgradeOptions) ([]OutdatedPackage, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	planned, err := c.planUpgrade(files, opts)
+	if err != nil {
+		return nil, err
+	}
+	var r []OutdatedPackage
+	for _, u := range planned {
+		r = append(r, OutdatedPackage{
+			Name:      u.Package.Name,
+			Category:  u.Package.Category,
+			Installed: u.installed.Tag,
+			Latest:    u.Package.GetLatestVersion(c.liquibase.Version).Tag,
+			Target:    u.Version.Tag,
+		})
+	}
+	return r, nil
+}
+
+// Resolve packages given as name, name@version or name@range and their dependencies without installing them
+func (c *Client) Resolve(specs ...string) ([]Resolution, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	var reqs []packages.Requirement
+	for _, spec := range specs {
+		p, v, _, err := c.parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, packages.Requirement{Name: p.Name, Constraint: v.Tag})
+	}
+	resolved, err := c.resolver(files, nil).Resolve(reqs)
+	if err != nil {
+		return nil, err
+	}
+	return toResolutions(resolved), nil
+}
+
+// Add install packages given as name, name@version or name@range with their dependencies
+// and record them in liquibase.json unless Global. With Replace a different installed version is swapped out.
+func (c *Client) Add(ctx context.Context, specs ...string) (Result, error) {
+	var res Result
+	files, err := c.files()
+	if err != nil {
+		return res, err
+	}
+	d, l, err := c.readProject()
+	if err != nil {
+		return res, err
+	}
+
+	var reqs []packages.Requirement
+	var replaced []packages.Resolution
+	for _, spec := range specs {
+		p, v, constraint, err := c.parse(spec)
+		if err != nil {
+			return res, err
+		}
+		if p.InClassPath(files) {
+			installed := p.GetInstalledVersion(files)
+			switch {
+			case c.opts.Replace && installed.Tag != v.Tag:
+				if err = c.checkDependents(files, p, v); err != nil {
+					return res, err
+				}
+				replaced = append(replaced, packages.Resolution{Package: p, Version: installed})
+				d.Remove(p.Name)
+			case c.opts.SkipExisting || c.opts.Replace:
+				res.Skipped = append(res.Skipped, toResolution(packages.Resolution{Package: p, Version: installed, Installed: true}))
+				continue
+			default:
+				return res, errors.New(errors.ErrAlreadyInstalled, p.Name+"@"+installed.Tag+" is already installed.\n"+
+					spec+" can not be installed.\nConsider running `lpm upgrade` or `lpm add "+spec+" --replace`.")
+			}
+		}
+		if constraint == "" {
+			constraint = v.Tag
+		}
+		reqs = append(reqs, packages.Requirement{Name: p.Name, Constraint: v.Tag})
+		d.Dependencies = append(d.Dependencies, dependencies.Dependency{p.Name: constraint})
+	}
+
+	// Resolve transitive dependencies and install them before the packages requiring them
+	resolved, err := c.resolver(without(files, replaced), nil).Resolve(reqs)
+	if err != nil {
+		return res, err
+	}
+	for _, r := range resolved {
+		l.Set(r.Package.Name, r.Version)
+	}
+	err = c.apply(ctx, resolved, replaced, &res, func() error { return c.writeProject(d, l) })
+	return res, err
+}
+
+// checkDependents error when an installed package depends on p with a constraint that v does not satisfy
+func (c *Client) checkDependents(files []fs.FileInfo, p packages.Package, v packages.Version) error {
+	for _, ip := range c.packs.GetInstalled(files) {
+		constraint, ok := ip.GetInstalledVersion(files).Dependencies[p.Name]
+		if ok && !packages.MatchesConstraint(v.Tag, constraint) {
+			return errors.Newf(errors.ErrConflict, "dependency conflict: %s requires %s@%s, %s@%s can not replace the installed version",
+				ip.Name, p.Name, constraint, p.Name, v.Tag)
+		}
+	}
+	return nil
+}
+
+// without classpath files other than the installed versions being replaced
+func without(files []fs.FileInfo, replaced []packages.Resolution) []fs.FileInfo {
+	var r []fs.FileInfo
+	for _, f := range files {
+		keep := true
+		for _, rp := range replaced {
+			if f.Name() == rp.Version.GetFilename() {
+				keep = false
+			}
+		}
+		if keep {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
+// Install packages listed in liquibase.json, preferring the versions pinned in liquibase.lock.json
+func (c *Client) Install(ctx context.Context) (Result, error) {
+	var res Result
+	if c.opts.Global {
+		return res, fmt.Errorf("Can not install packages from liquibase.json globally")
+	}
+	files, err := c.files()
+	if err != nil {
+		return res, err
+	}
+	d, l, err := c.readProject()
+	if err != nil {
+		return res, err
+	}
+
+	var reqs []packages.Requirement
+	for _, dep := range d.Dependencies {
+		reqs = append(reqs, packages.Requirement{Name: dep.GetName(), Constraint: dep.GetVersion()})
+	}
+	resolved, err := c.resolver(files, l.Versions()).Resolve(reqs)
+	if err != nil {
+		return res, err
+	}
+	for _, r := range resolved {
+		if r.Installed && r.RequiredBy == "" {
+			return res, errors.New(errors.ErrAlreadyInstalled, r.Package.Name+" is already installed.")
+		}
+	}
+	nl := dependencies.Lockfile{}
+	for _, r := range resolved {
+		nl.Set(r.Package.Name, r.Version)
+	}
+	err = c.apply(ctx, resolved, nil, &res, func() error { return nl.WriteFile(c.lockFile()) })
+	return res, err
+}
+
+// Remove uninstall packages and drop them from liquibase.json unless Global
+func (c *Client) Remove(names ...string) (Result, error) {
+	var res Result
+	files, err := c.files()
+	if err != nil {
+		return res, err
+	}
+	d, l, err := c.readProject()
+	if err != nil {
+		return res, err
+	}
+	for _, name := range names {
+		p := c.packs.GetByName(name)
+		if p.Name == "" {
+			return res, errors.New(errors.ErrPackageNotFound, "Package '"+name+"' not found.")
+		}
+		v := p.GetInstalledVersion(files)
+		if !v.InClassPath(files) {
+			return res, errors.New(errors.ErrNotInstalled, name+" is not installed.")
+		}
+		if err = p.Remove(c.opts.Classpath, v); err != nil {
+			return res, fmt.Errorf("Unable to remove %s from classpath.", v.GetFilename())
+		}
+		res.Removed = append(res.Removed, toResolution(packages.Resolution{Package: p, Version: v}))
+		d.Remove(p.Name)
+		l.Remove(p.Name)
+	}
+	return res, c.writeProject(d, l)
+}
+
+// Upgrade installed packages to their latest compatible versions.
+// Every new version is installed before any old one is removed.
+func (c *Client) Upgrade(ctx context.Context) (Result, error) {
+	return c.UpgradeWith(ctx, UpgradeOptions{})
+}
+
+// UpgradeWith upgrade installed packages selected by opts to their newest allowed versions and record them in
+// liquibase.json. Every new version is installed before any old one is removed.
+func (c *Client) UpgradeWith(ctx context.Context, opts UpgradeOptions) (Result, error) {
+	var res Result
+	files, err := c.files()
+	if err != nil {
+		return res, err
+	}
+	planned, err := c.planUpgrade(files, opts)
+	if err != nil || len(planned) == 0 {
+		return res, err
+	}
+	d, l, err := c.readProject()
+	if err != nil {
+		return res, err
+	}
+	var reqs []packages.Requirement
+	var old []packages.Resolution
+	for _, u := range planned {
+		p, target := u.Package, u.Version
+		reqs = append(reqs, packages.Requirement{Name: p.Name, Constraint: target.Tag})
+		old = append(old, packages.Resolution{Package: p, Version: u.installed})
+		// Keep version ranges from liquibase.json that still allow the upgraded version
+		constraint := u.constraint
+		if constraint == "" {
+			constraint = target.Tag
+			if rc := d.Get(p.Name).GetVersion(); packages.IsRange(rc) && packages.MatchesConstraint(target.Tag, rc) {
+				constraint = rc
+			}
+		}
+		d.Remove(p.Name)
+		d.Dependencies = append(d.Dependencies, dependencies.Dependency{p.Name: constraint})
+	}
+
+	// New versions may have dependencies of their own, install them before the packages requiring them
+	resolved, err := c.resolver(without(files, old), nil).Resolve(reqs)
+	if err != nil {
+		return res, err
+	}
+	for _, r := range resolved {
+		l.Set(r.Package.Name, r.Version)
+	}
+	err = c.apply(ctx, resolved, old, &res, func() error { return c.writeProject(d, l) })
+	return res, err
+}
+
+// planUpgrade target versions of the installed packages selected by opts, skipping packages already at their target
+func (c *Client) planUpgrade(files []fs.FileInfo, opts UpgradeOptions) ([]upgrade, error) {
+	if opts.To != "" && len(opts.Packages) != 1 {
+		return nil, fmt.Errorf("A target version requires exactly one package.")
+	}
+	candidates := c.packs.GetInstalled(files)
+	if len(opts.Packages) > 0 {
+		candidates = nil
+		for _, name := range opts.Packages {
+			p := c.packs.GetByName(name)
+			if p.Name == "" {
+				return nil, errors.New(errors.ErrPackageNotFound, "Package '"+name+"' not found.")
+			}
+			if !p.InClassPath(files) {
+				return nil, errors.New(errors.ErrNotInstalled, name+" is not installed.")
+			}
+			candidates = append(candidates, p)
+		}
+	}
+	var r []upgrade
+	for _, p := range candidates {
+		u := upgrade{installed: p.GetInstalledVersion(files)}
+		u.Package = p
+		if opts.To != "" {
+			_, v, constraint, err := c.parse(p.Name + "@" + opts.To)
+			if err != nil {
+				return nil, err
+			}
+			if newer(u.installed.Tag, v.Tag) {
+				return nil, errors.New(errors.ErrConflict, p.Name+"@"+v.Tag+" is older than the installed version "+u.installed.Tag+".")
+			}
+			u.Version, u.constraint = v, constraint
+		} else {
+			bound, err := packages.PolicyConstraint(u.installed.Tag, opts.Policy)
+			if err != nil {
+				return nil, err
+			}
+			if u.Version, err = p.ResolveVersion(bound, c.liquibase.Version); err != nil {
+				return nil, err
+			}
+		}
+		if newer(u.Version.Tag, u.installed.Tag) {
+			r = append(r, u)
+		}
+	}
+	return r, nil
+}
+
+// newer version tag a is greater than b
+func newer(a string, b string) bool {
+	av, err := version.NewVersion(a)
+	if err != nil {
+		return false
+	}
+	bv, err := version.NewVersion(b)
+	if err != nil {
+		return false
+	}
+	return av.GreaterThan(bv)
+}
+
+// parse package spec into package, selected version and the range to record in liquibase.json
+func (c *Client) parse(spec string) (packages.Package, packages.Version, string, error) {
+	name, requested, pinned := strings.Cut(spec, "@")
+	p := c.packs.GetByName(name)
+	if p.Name == "" {
+		return p, packages.Version{}, "", errors.New(errors.ErrPackageNotFound, "Package '"+spec+"' not found.")
+	}
+	lb := c.liquibase.Version
+	if !pinned {
+		v := p.GetLatestVersion(lb)
+		if v.Tag == "" {
+			versionStr := "unknown"
+			if lb != nil {
+				versionStr = lb.String()
+			}
+			return p, v, "", errors.New(errors.ErrIncompatible, "Unable to find compatible version of "+spec+" for liquibase v"+versionStr+". Please consider updating liquibase.")
+		}
+		return p, v, "", nil
+	}
+	v, err := p.ResolveVersion(requested, lb)
+	if err != nil {
+		return p, v, "", errors.Newf(errors.ErrPackageNotFound, "Invalid version '%s': %w", requested, err)
+	}
+	if v.Tag == "" {
+		return p, v, "", errors.New(errors.ErrPackageNotFound, "Version '"+requested+"' not available.")
+	}
+	if !p.IsCompatible(v, lb) {
+		return p, v, "", errors.New(errors.ErrIncompatible, spec+" is not compatible with liquibase v"+lb.String()+". Please consider updating liquibase.")
+	}
+	var constraint string
+	if packages.IsRange(requested) {
+		constraint = requested
+	}
+	return p, v, constraint, nil
+}
+
+// apply install resolved versions missing from classpath and remove old versions as one transaction,
+// running write last. The previous jars and project files are restored when any step fails.
+func (c *Client) apply(ctx context.Context, resolved []packages.Resolution, old []packages.Resolution, res *Result, write func() error) error {
+	if err := c.requireLocal(resolved); err != nil {
+		return err
+	}
+	t := packages.Begin(c.opts.Classpath, c.install)
+	if !c.opts.Global {
+		if err := t.Track(c.projectFile(), c.lockFile()); err != nil {
+			return err
+		}
+	}
+	for _, r := range resolved {
+		if !r.Installed {
+			t.Install(r.Version)
+		}
+	}
+	for _, r := range old {
+		t.Remove(r.Version)
+	}
+	var progress *utils.Progress
+	if c.opts.Progress != nil {
+		progress = utils.NewProgress(c.opts.Progress)
+	}
+	if err := t.Apply(ctx, c.opts.Jobs, progress, write); err != nil {
+		return err
+	}
+	for _, r := range resolved {
+		if !r.Installed {
+			res.Installed = append(res.Installed, toResolution(r))
+		}
+	}
+	for _, r := range old {
+		res.Removed = append(res.Removed, toResolution(r))
+	}
+	for _, v := range t.Unverified() {
+		for _, r := range resolved {
+			if r.Version.Path == v.Path {
+				res.Unverified = append(res.Unverified, toResolution(r))
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) resolver(files []fs.FileInfo, locked map[string]packages.Version) packages.Resolver {
+	return packages.Resolver{
+		Packages:  c.packs,
+		Liquibase: c.liquibase.Version,
+		Installed: files,
+		Locked:    locked,
+	}
+}
+
+// files current contents of classpath
+func (c *Client) files() ([]fs.FileInfo, error) {
+	files, err := utils.ReadDir(c.opts.Classpath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return files, nil
+}
+
+// projectClasspath local classpath from the classpath field of liquibase.json in project, <project>/liquibase_libs when unset
+func projectClasspath(project string) (string, error) {
+	d := dependencies.Dependencies{}
+	if err := d.ReadFile(filepath.Join(project, "liquibase.json")); err != nil {
+		return "", err
+	}
+	switch {
+	case d.Classpath == "":
+		return filepath.Join(project, "liquibase_libs"), nil
+	case filepath.IsAbs(d.Classpath):
+		return d.Classpath, nil
+	}
+	return filepath.Join(project, d.Classpath), nil
+}
+
+// readProject load liquibase.json and liquibase.lock.json, both are empty when Global
+func (c *Client) readProject() (dependencies.Dependencies, dependencies.Lockfile, error) {
+	d := dependencies.Dependencies{}
+	l := dependencies.Lockfile{}
+	if c.opts.Global {
+		return d, l, nil
+	}
+	if err := d.ReadFile(c.projectFile()); err != nil {
+		return d, l, err
+	}
+	return d, l, l.ReadFile(c.lockFile())
+}
+
+// writeProject save liquibase.json and liquibase.lock.json unless Global
+func (c *Client) writeProject(d dependencies.Dependencies, l dependencies.Lockfile) error {
+	if c.opts.Global {
+		return nil
+	}
+	if err := d.WriteFile(c.projectFile()); err != nil {
+		return err
+	}
+	return l.WriteFile(c.lockFile())
+}
+
+// projectFile liquibase.json of the project
+func (c *Client) projectFile() string {
+	return filepath.Join(c.opts.Project, "liquibase.json")
+}
+
+// lockFile liquibase.lock.json of the project
+func (c *Client) lockFile() string {
+	return filepath.Join(c.opts.Project, "liquibase.lock.json")
+}
+
+// requireLocal error listing every artifact that can not be installed without network access
+func (c *Client) requireLocal(resolved []packages.Resolution) error {
+	if !c.opts.Offline {
+		return nil
+	}
+	var missing []string
+	for _, r := range resolved {
+		if r.Installed || r.Version.AvailableLocally(c.install) {
+			continue
+		}
+		missing = append(missing, "  "+r.Package.Name+"@"+r.Version.Tag+" ("+r.Version.Path+")")
+	}
+	if len(missing) > 0 {
+		return errors.New(errors.ErrOffline, "Unable to install in offline mode. The following artifacts are not in the download cache or a local path:\n"+strings.Join(missing, "\n"))
+	}
+	return nil
+}
+
+// manifestSource packages.json location, seeding the default location with the embedded manifest
+func manifestSource(source string, libpath string) (string, error) {
+	if source != "" {
+		return source, nil
+	}
+	if !app.PackagesInClassPath(libpath) {
+		if err := app.CopyPackagesToClassPath(libpath, app.PackagesJSON); err != nil {
+			return "", err
+		}
+	}
+	return libpath + app.PackageFile, nil
+}
+
+func toResolution(r packages.Resolution) Resolution {
+	return Resolution{
+		Name:       r.Package.Name,
+		Category:   r.Package.Category,
+		Version:    r.Version.Tag,
+		Filename:   r.Version.GetFilename(),
+		Source:     r.Version.Path,
+		RequiredBy: r.RequiredBy,
+		Installed:  r.Installed,
+	}
+}
+
+func toResolutions(rs []packages.Resolution) []Resolution {
+	var r []Resolution
+	for _, e := range rs {
+		r = append(r, toResolution(e))
+	}
+	return r
+}
+
+func withSeparator(p string) string {
+	if strings.HasSuffix(p, "/") || strings.HasSuffix(p, string(os.PathSeparator)) {
+		return p
+	}
+	return p + string(os.PathSeparator)
+}
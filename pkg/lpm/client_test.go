@@ -0,0 +1,345 @@
+package lpm
+
+import (
+	"context"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/liquibase/liquibase-package-manager/internal/app/errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const manifest = `[
+ {"name":"alpha","category":"driver","versions":[
+  {"tag":"1.0.0","path":"{{dir}}/alpha-1.0.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0"},
+  {"tag":"1.1.0","path":"{{dir}}/alpha-1.1.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0"}]},
+ {"name":"beta","category":"extension","versions":[
+  {"tag":"2.0.0","path":"{{dir}}/beta-2.0.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0","dependencies":{"alpha":"~1.0"}}]},
+ {"name":"epsilon","category":"extension","versions":[
+  {"tag":"1.0.0","path":"{{dir}}/epsilon-1.0.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0"},
+  {"tag":"2.0.0","path":"{{dir}}/epsilon-2.0.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0","dependencies":{"beta":"^2.0"}}]},
+ {"name":"gamma","category":"driver","versions":[
+  {"tag":"1.0.0","path":"{{dir}}/missing/gamma-1.0.0.jar","algorithm":"SHA1","liquibaseCore":"0.0.0"}]}
+]`
+
+// newTestClient client for an empty Liquibase home and project with local artifacts
+func newTestClient(t *testing.T) (*Client, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range []string{"alpha-1.0.0.jar", "alpha-1.1.0.jar", "beta-2.0.0.jar", "epsilon-1.0.0.jar", "epsilon-2.0.0.jar"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(f), 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := filepath.Join(dir, "packages.json")
+	os.WriteFile(m, []byte(strings.ReplaceAll(manifest, "{{dir}}", dir)), 0664)
+	home := filepath.Join(dir, "liquibase")
+	os.MkdirAll(filepath.Join(home, "lib"), 0775)
+	project := filepath.Join(dir, "project")
+	os.Mkdir(project, 0775)
+
+	c, err := New(Options{Home: home, Project: project, Manifest: m})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c, project
+}
+
+func names(rs []Resolution) string {
+	var r []string
+	for _, e := range rs {
+		r = append(r, e.Name+"@"+e.Version)
+	}
+	return strings.Join(r, " ")
+}
+
+func TestClient_Resolve(t *testing.T) {
+	c, _ := newTestClient(t)
+	got, err := c.Resolve("beta")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if names(got) != "alpha@1.0.0 beta@2.0.0" || got[0].RequiredBy != "beta" {
+		t.Errorf("Resolve() = %v", got)
+	}
+	if _, err = c.Resolve("delta"); !errors.Is(err, errors.ErrPackageNotFound) {
+		t.Errorf("Resolve() error = %v, want %v", err, errors.ErrPackageNotFound)
+	}
+}
+
+func TestClient_AddInstallRemove(t *testing.T) {
+	c, project := newTestClient(t)
+	ctx := context.Background()
+
+	res, err := c.Add(ctx, "beta")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.0.0 beta@2.0.0" {
+		t.Errorf("Add() installed %v", res.Installed)
+	}
+	if _, err = c.Add(ctx, "beta"); !errors.Is(err, errors.ErrAlreadyInstalled) {
+		t.Errorf("Add() error = %v, want %v", err, errors.ErrAlreadyInstalled)
+	}
+	for _, f := range []string{"liquibase.json", "liquibase.lock.json"} {
+		if _, err = os.Stat(filepath.Join(project, f)); err != nil {
+			t.Errorf("Expected %s to be written: %v", f, err)
+		}
+	}
+
+	os.RemoveAll(c.Classpath())
+	os.Remove(filepath.Join(project, "liquibase.lock.json"))
+	if res, err = c.Install(ctx); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.0.0 beta@2.0.0" {
+		t.Errorf("Install() installed %v", res.Installed)
+	}
+	if _, err = os.Stat(filepath.Join(project, "liquibase.lock.json")); err != nil {
+		t.Errorf("Expected Install() to write the project lockfile: %v", err)
+	}
+
+	if res, err = c.Remove("beta"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if names(res.Removed) != "beta@2.0.0" {
+		t.Errorf("Remove() removed %v", res.Removed)
+	}
+	installed, _ := c.List()
+	if len(installed) != 1 || installed[0].Name != "alpha" || installed[0].Filename != "alpha-1.0.0.jar" {
+		t.Errorf("List() = %v", installed)
+	}
+}
+
+func TestClient_Upgrade(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+	if _, err := c.Add(ctx, "alpha@1.0.0"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	outdated, _ := c.Outdated()
+	if len(outdated) != 1 || outdated[0].Installed != "1.0.0" || outdated[0].Latest != "1.1.0" {
+		t.Fatalf("Outdated() = %v", outdated)
+	}
+	res, err := c.Upgrade(ctx)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.1.0" || names(res.Removed) != "alpha@1.0.0" {
+		t.Errorf("Upgrade() = %v", res)
+	}
+	if outdated, _ = c.Outdated(); len(outdated) != 0 {
+		t.Errorf("Expected no outdated packages after Upgrade() but got %v", outdated)
+	}
+}
+
+func TestClient_UpgradeDependencies(t *testing.T) {
+	c, project := newTestClient(t)
+	ctx := context.Background()
+	if _, err := c.Add(ctx, "epsilon@1.0.0"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	res, err := c.UpgradeWith(ctx, UpgradeOptions{Packages: []string{"epsilon"}, Policy: Major})
+	if err != nil {
+		t.Fatalf("UpgradeWith() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.0.0 beta@2.0.0 epsilon@2.0.0" || names(res.Removed) != "epsilon@1.0.0" {
+		t.Errorf("UpgradeWith() = %v", res)
+	}
+	b, _ := os.ReadFile(filepath.Join(project, "liquibase.lock.json"))
+	for _, name := range []string{"alpha", "beta", "epsilon"} {
+		if !strings.Contains(string(b), `"`+name+`"`) {
+			t.Errorf("Expected %s in liquibase.lock.json but got %s", name, b)
+		}
+	}
+}
+
+func TestClient_UpgradeSelective(t *testing.T) {
+	c, project := newTestClient(t)
+	ctx := context.Background()
+	if _, err := c.Add(ctx, "alpha@1.0.0"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := c.UpgradeWith(ctx, UpgradeOptions{Packages: []string{"beta"}}); !errors.Is(err, errors.ErrNotInstalled) {
+		t.Errorf("UpgradeWith() error = %v, want %v", err, errors.ErrNotInstalled)
+	}
+	if _, err := c.UpgradeWith(ctx, UpgradeOptions{To: "1.1.0"}); err == nil {
+		t.Errorf("Expected UpgradeWith() with a target version and no package to fail")
+	}
+	if planned, _ := c.PlanUpgrade(UpgradeOptions{Policy: Patch}); len(planned) != 0 {
+		t.Errorf("Expected no patch upgrades but got %v", planned)
+	}
+	if planned, _ := c.PlanUpgrade(UpgradeOptions{Policy: Minor}); len(planned) != 1 || planned[0].Target != "1.1.0" {
+		t.Errorf("PlanUpgrade() = %v", planned)
+	}
+
+	res, err := c.UpgradeWith(ctx, UpgradeOptions{Packages: []string{"alpha"}, To: "~1.1"})
+	if err != nil {
+		t.Fatalf("UpgradeWith() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.1.0" {
+		t.Errorf("UpgradeWith() = %v", res)
+	}
+	b, _ := os.ReadFile(filepath.Join(project, "liquibase.json"))
+	if !strings.Contains(string(b), `"~1.1"`) {
+		t.Errorf("Expected target range in liquibase.json but got %s", b)
+	}
+	if _, err = c.UpgradeWith(ctx, UpgradeOptions{Packages: []string{"alpha"}, To: "1.0.0"}); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("UpgradeWith() error = %v, want %v", err, errors.ErrConflict)
+	}
+}
+
+func TestClient_AddReplace(t *testing.T) {
+	c, project := newTestClient(t)
+	ctx := context.Background()
+	if _, err := c.Add(ctx, "beta"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := c.Add(ctx, "alpha@1.1.0"); !errors.Is(err, errors.ErrAlreadyInstalled) {
+		t.Errorf("Add() error = %v, want %v", err, errors.ErrAlreadyInstalled)
+	}
+	c.opts.Replace = true
+	if _, err := c.Add(ctx, "alpha@1.1.0"); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("Add() error = %v, want %v", err, errors.ErrConflict)
+	}
+	if _, err := c.Remove("beta"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	res, err := c.Add(ctx, "alpha@1.1.0")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if names(res.Installed) != "alpha@1.1.0" || names(res.Removed) != "alpha@1.0.0" {
+		t.Errorf("Add() = %v", res)
+	}
+	if res, err = c.Add(ctx, "alpha@1.0.0"); err != nil || names(res.Installed) != "alpha@1.0.0" {
+		t.Errorf("Add() = %v, error = %v", res, err)
+	}
+	installed, _ := c.List()
+	if len(installed) != 1 || installed[0].Version != "1.0.0" {
+		t.Errorf("List() = %v", installed)
+	}
+	b, _ := os.ReadFile(filepath.Join(project, "liquibase.json"))
+	if strings.Count(string(b), `"alpha"`) != 1 || !strings.Contains(string(b), `"1.0.0"`) {
+		t.Errorf("Expected the replaced version in liquibase.json but got %s", b)
+	}
+}
+
+func TestClient_AddRollback(t *testing.T) {
+	c, project := newTestClient(t)
+	ctx := context.Background()
+	if _, err := c.Add(ctx, "alpha@1.0.0"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	before, _ := os.ReadFile(filepath.Join(project, "liquibase.json"))
+
+	if _, err := c.Add(ctx, "beta", "gamma"); err == nil {
+		t.Fatalf("Expected Add() to fail on missing artifact")
+	}
+	installed, _ := c.List()
+	if len(installed) != 1 || installed[0].Name != "alpha" {
+		t.Errorf("Expected only alpha to stay installed but got %v", installed)
+	}
+	after, _ := os.ReadFile(filepath.Join(project, "liquibase.json"))
+	if string(before) != string(after) {
+		t.Errorf("Expected liquibase.json to be unchanged but got %s", after)
+	}
+}
+
+func TestClient_ProjectClasspath(t *testing.T) {
+	c, project := newTestClient(t)
+	os.WriteFile(filepath.Join(project, "liquibase.json"), []byte(`{"classpath":"db/lib","dependencies":[]}`), 0664)
+	opts := c.opts
+	opts.Classpath = ""
+	c, err := New(opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if want := filepath.Join(project, "db", "lib") + string(os.PathSeparator); c.Classpath() != want {
+		t.Errorf("Classpath() = %s, want %s", c.Classpath(), want)
+	}
+	if _, err = c.Add(context.Background(), "alpha"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(project, "db", "lib", "alpha-1.1.0.jar")); err != nil {
+		t.Errorf("alpha not installed in db/lib: %v", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(project, "liquibase.json")); !strings.Contains(string(b), `"classpath": "db/lib"`) {
+		t.Errorf("liquibase.json lost classpath: %s", b)
+	}
+}
+
+func TestClient_Independent(t *testing.T) {
+	a, first := newTestClient(t)
+	b, second := newTestClient(t)
+	ctx := context.Background()
+	if _, err := a.Add(ctx, "alpha"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := b.Add(ctx, "beta"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if got, _ := a.List(); len(got) != 1 || got[0].Name != "alpha" {
+		t.Errorf("List() = %v, want alpha only", got)
+	}
+	if d, _ := os.ReadFile(filepath.Join(first, "liquibase.json")); strings.Contains(string(d), "beta") {
+		t.Errorf("first project tracks packages of second client: %s", d)
+	}
+	if d, _ := os.ReadFile(filepath.Join(second, "liquibase.json")); !strings.Contains(string(d), "beta") {
+		t.Errorf("second project does not track beta: %s", d)
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	c, _ := newTestClient(t)
+	tests := []struct {
+		name string
+		opts func(o *Options)
+		want error
+	}{
+		{name: "Can Reject Unknown Algorithm", opts: func(o *Options) { o.MinAlgorithm = "MD5" }, want: errors.ErrUnknownAlgorithm},
+		{name: "Can Refuse Remote Manifest Offline", opts: func(o *Options) { o.Offline, o.Manifest = true, "https://example.com/packages.json" }, want: errors.ErrOffline},
+		{name: "Can Detect Missing Keyring", opts: func(o *Options) { o.Keyring = filepath.Join(t.TempDir(), "missing.asc") }, want: os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := c.opts
+			tt.opts(&opts)
+			if _, err := New(opts); !errors.Is(err, tt.want) {
+				t.Errorf("New() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Signatures(t *testing.T) {
+	c, _ := newTestClient(t)
+	res, err := c.Add(context.Background(), "alpha")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if names(res.Unverified) != "alpha@1.1.0" {
+		t.Errorf("Unverified = %v, want alpha@1.1.0", res.Unverified)
+	}
+
+	e, err := openpgp.NewEntity("lpm test", "", "test@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := filepath.Join(t.TempDir(), "keyring.gpg")
+	f, _ := os.Create(keyring)
+	e.Serialize(f)
+	f.Close()
+	c, _ = newTestClient(t)
+	opts := c.opts
+	opts.Keyring = keyring
+	if c, err = New(opts); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err = c.Add(context.Background(), "beta"); !errors.Is(err, errors.ErrUnsigned) {
+		t.Errorf("Add() error = %v, want %v", err, errors.ErrUnsigned)
+	}
+}
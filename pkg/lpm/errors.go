@@ -0,0 +1,20 @@
+package lpm
+
+import "github.com/liquibase/liquibase-package-manager/internal/app/errors"
+
+// Errors returned by Client, match with errors.Is
+var (
+	ErrPackageNotFound  = errors.ErrPackageNotFound
+	ErrIncompatible     = errors.ErrIncompatible
+	ErrChecksumMismatch = errors.ErrChecksumMismatch
+	ErrCorrupt          = errors.ErrCorrupt
+	ErrBadSignature     = errors.ErrBadSignature
+	ErrUnsigned         = errors.ErrUnsigned
+	ErrUnknownAlgorithm = errors.ErrUnknownAlgorithm
+	ErrWeakChecksum     = errors.ErrWeakChecksum
+	ErrNetwork          = errors.ErrNetwork
+	ErrOffline          = errors.ErrOffline
+	ErrConflict         = errors.ErrConflict
+	ErrAlreadyInstalled = errors.ErrAlreadyInstalled
+	ErrNotInstalled     = errors.ErrNotInstalled
+)